@@ -1,10 +1,11 @@
 package tonicpow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
+	"strconv"
 )
 
 // permitFields will remove fields that cannot be used
@@ -19,16 +20,23 @@ func (a *AdvertiserProfile) permitFields() {
 // For more information: https://docs.tonicpow.com/#b3a62d35-7778-4314-9321-01f5266c3b51
 func (c *Client) GetAdvertiserProfile(profileID uint64) (profile *AdvertiserProfile,
 	response *StandardResponse, err error) {
+	return c.GetAdvertiserProfileWithContext(context.Background(), profileID)
+}
+
+// GetAdvertiserProfileWithContext is the same as GetAdvertiserProfile, but uses the given context for the request
+func (c *Client) GetAdvertiserProfileWithContext(ctx context.Context, profileID uint64) (profile *AdvertiserProfile,
+	response *StandardResponse, err error) {
 
 	// Must have an ID
 	if profileID == 0 {
-		err = fmt.Errorf("missing field: %s", fieldID)
+		err = newMissingAttributeError(fieldID)
 		return
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
+	ctx = withOperation(ctx, Operation{Name: "GetAdvertiserProfile", AdvertiserProfileID: profileID})
+	if response, err = c.RequestWithContext(
+		ctx, http.MethodGet,
 		fmt.Sprintf("/%s/details/%d", modelAdvertiser, profileID),
 		nil, http.StatusOK,
 	); err != nil {
@@ -44,18 +52,24 @@ func (c *Client) GetAdvertiserProfile(profileID uint64) (profile *AdvertiserProf
 //
 // For more information: https://docs.tonicpow.com/#0cebd1ff-b1ce-4111-aff6-9d586f632a84
 func (c *Client) UpdateAdvertiserProfile(profile *AdvertiserProfile) (*StandardResponse, error) {
+	return c.UpdateAdvertiserProfileWithContext(context.Background(), profile)
+}
+
+// UpdateAdvertiserProfileWithContext is the same as UpdateAdvertiserProfile, but uses the given context for the request
+func (c *Client) UpdateAdvertiserProfileWithContext(ctx context.Context, profile *AdvertiserProfile) (*StandardResponse, error) {
 
 	// Basic requirements
 	if profile.ID == 0 {
-		return nil, fmt.Errorf("missing required attribute: %s", fieldID)
+		return nil, newMissingAttributeError(fieldID)
 	}
 
 	// Permit fields
 	profile.permitFields()
 
 	// Fire the Request
-	response, err := c.Request(
-		http.MethodPut,
+	ctx = withOperation(ctx, Operation{Name: "UpdateAdvertiserProfile", AdvertiserProfileID: profile.ID})
+	response, err := c.RequestWithContext(
+		ctx, http.MethodPut,
 		"/"+modelAdvertiser,
 		profile, http.StatusOK,
 	)
@@ -72,33 +86,63 @@ func (c *Client) UpdateAdvertiserProfile(profile *AdvertiserProfile) (*StandardR
 // For more information: https://docs.tonicpow.com/#98017e9a-37dd-4810-9483-b6c400572e0c
 func (c *Client) ListCampaignsByAdvertiserProfile(profileID uint64, page, resultsPerPage int,
 	sortBy, sortOrder string) (campaigns *CampaignResults, response *StandardResponse, err error) {
+	return c.ListCampaignsByAdvertiserProfileWithContext(context.Background(), profileID, page, resultsPerPage, sortBy, sortOrder)
+}
+
+// ListCampaignsByAdvertiserProfileWithContext is the same as ListCampaignsByAdvertiserProfile, but uses the given context for the request
+func (c *Client) ListCampaignsByAdvertiserProfileWithContext(ctx context.Context, profileID uint64, page, resultsPerPage int,
+	sortBy, sortOrder string) (campaigns *CampaignResults, response *StandardResponse, err error) {
+
+	// Basic requirements
+	if profileID == 0 {
+		err = newMissingAttributeError(fieldAdvertiserProfileID)
+		return
+	}
+
+	// Do we know these fields?
+	if err = validateList(page, resultsPerPage, sortBy, sortOrder, campaignSortFields); err != nil {
+		return
+	}
+	sortBy, sortOrder = sortDefaults(sortBy, sortOrder)
+
+	return c.listCampaignsByAdvertiserProfile(ctx, profileID, orderedQuery(
+		fieldCurrentPage, strconv.Itoa(page),
+		fieldResultsPerPage, strconv.Itoa(resultsPerPage),
+		fieldSortBy, sortBy,
+		fieldSortOrder, sortOrder,
+	))
+}
+
+// ListCampaignsByAdvertiserProfileWithQuery will return a list of campaigns using the query
+// Pagination & sorting defaults are applied (see ListAdvertiserCampaignsQuery)
+//
+// For more information: https://docs.tonicpow.com/#98017e9a-37dd-4810-9483-b6c400572e0c
+func (c *Client) ListCampaignsByAdvertiserProfileWithQuery(ctx context.Context, profileID uint64,
+	query ListAdvertiserCampaignsQuery) (campaigns *CampaignResults, response *StandardResponse, err error) {
 
 	// Basic requirements
 	if profileID == 0 {
-		err = fmt.Errorf("missing required attribute: %s", fieldAdvertiserProfileID)
+		err = newMissingAttributeError(fieldAdvertiserProfileID)
 		return
 	}
 
-	// Do we know this field?
-	if len(sortBy) > 0 {
-		if !isInList(strings.ToLower(sortBy), campaignSortFields) {
-			err = fmt.Errorf("sort by %s is not valid", sortBy)
-			return
-		}
-	} else {
-		sortBy = SortByFieldCreatedAt
-		sortOrder = SortOrderDesc
+	// Do we know these fields?
+	if err = query.Validate(); err != nil {
+		return
 	}
 
+	return c.listCampaignsByAdvertiserProfile(ctx, profileID, query.withDefaults().Encode())
+}
+
+// listCampaignsByAdvertiserProfile will fire the list request using the encoded query
+func (c *Client) listCampaignsByAdvertiserProfile(ctx context.Context, profileID uint64,
+	query string) (campaigns *CampaignResults, response *StandardResponse, err error) {
+
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
-		fmt.Sprintf("/%s/%s/%d?%s=%d&%s=%d&%s=%s&%s=%s", modelAdvertiser, modelCampaign, profileID,
-			fieldCurrentPage, page,
-			fieldResultsPerPage, resultsPerPage,
-			fieldSortBy, sortBy,
-			fieldSortOrder, sortOrder,
-		),
+	ctx = withOperation(ctx, Operation{Name: "ListCampaignsByAdvertiserProfile", AdvertiserProfileID: profileID})
+	if response, err = c.RequestWithContext(
+		ctx, http.MethodGet,
+		fmt.Sprintf("/%s/%s/%d?%s", modelAdvertiser, modelCampaign, profileID, query),
 		nil, http.StatusOK,
 	); err != nil {
 		return
@@ -114,36 +158,68 @@ func (c *Client) ListCampaignsByAdvertiserProfile(profileID uint64, page, result
 // For more information: https://docs.tonicpow.com/#9c9fa8dc-3017-402e-8059-136b0eb85c2e
 func (c *Client) ListAppsByAdvertiserProfile(profileID uint64, page, resultsPerPage int,
 	sortBy, sortOrder string) (apps *AppResults, response *StandardResponse, err error) {
+	return c.ListAppsByAdvertiserProfileWithContext(context.Background(), profileID, page, resultsPerPage, sortBy, sortOrder)
+}
+
+// ListAppsByAdvertiserProfileWithContext is the same as ListAppsByAdvertiserProfile, but uses the given context for the request
+func (c *Client) ListAppsByAdvertiserProfileWithContext(ctx context.Context, profileID uint64, page, resultsPerPage int,
+	sortBy, sortOrder string) (apps *AppResults, response *StandardResponse, err error) {
+
+	// Basic requirements
+	if profileID == 0 {
+		err = newMissingAttributeError(fieldAdvertiserProfileID)
+		return
+	}
+
+	// Do we know these fields?
+	if err = validateList(page, resultsPerPage, sortBy, sortOrder, appSortFields); err != nil {
+		return
+	}
+	sortBy, sortOrder = sortDefaults(sortBy, sortOrder)
+
+	return c.listAppsByAdvertiserProfile(ctx, profileID, orderedQuery(
+		fieldID, strconv.FormatUint(profileID, 10),
+		fieldCurrentPage, strconv.Itoa(page),
+		fieldResultsPerPage, strconv.Itoa(resultsPerPage),
+		fieldSortBy, sortBy,
+		fieldSortOrder, sortOrder,
+	))
+}
+
+// ListAppsByAdvertiserProfileWithQuery will return a list of apps using the query
+// Pagination & sorting defaults are applied (see ListAppsQuery)
+//
+// For more information: https://docs.tonicpow.com/#9c9fa8dc-3017-402e-8059-136b0eb85c2e
+func (c *Client) ListAppsByAdvertiserProfileWithQuery(ctx context.Context, profileID uint64,
+	query ListAppsQuery) (apps *AppResults, response *StandardResponse, err error) {
 
 	// Basic requirements
 	if profileID == 0 {
-		err = fmt.Errorf("missing required attribute: %s", fieldAdvertiserProfileID)
+		err = newMissingAttributeError(fieldAdvertiserProfileID)
 		return
 	}
 
-	// Do we know this field?
-	if len(sortBy) > 0 {
-		if !isInList(strings.ToLower(sortBy), appSortFields) {
-			err = fmt.Errorf("sort by %s is not valid", sortBy)
-			return
-		}
-	} else {
-		sortBy = SortByFieldCreatedAt
-		sortOrder = SortOrderDesc
+	// Do we know these fields?
+	if err = query.Validate(); err != nil {
+		return
 	}
 
+	// Add the profile id (not part of the saved query)
+	values := query.withDefaults().Values()
+	values.Set(fieldID, strconv.FormatUint(profileID, 10))
+
+	return c.listAppsByAdvertiserProfile(ctx, profileID, values.Encode())
+}
+
+// listAppsByAdvertiserProfile will fire the list request using the encoded query (including the profile id)
+func (c *Client) listAppsByAdvertiserProfile(ctx context.Context, profileID uint64,
+	query string) (apps *AppResults, response *StandardResponse, err error) {
+
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
-		fmt.Sprintf(
-			"/%s/%s/?%s=%d&%s=%d&%s=%d&%s=%s&%s=%s",
-			modelAdvertiser, modelApp,
-			fieldID, profileID,
-			fieldCurrentPage, page,
-			fieldResultsPerPage, resultsPerPage,
-			fieldSortBy, sortBy,
-			fieldSortOrder, sortOrder,
-		),
+	ctx = withOperation(ctx, Operation{Name: "ListAppsByAdvertiserProfile", AdvertiserProfileID: profileID})
+	if response, err = c.RequestWithContext(
+		ctx, http.MethodGet,
+		fmt.Sprintf("/%s/%s/?%s", modelAdvertiser, modelApp, query),
 		nil, http.StatusOK,
 	); err != nil {
 		return
@@ -153,3 +229,23 @@ func (c *Client) ListAppsByAdvertiserProfile(profileID uint64, page, resultsPerP
 	err = json.Unmarshal(response.Body, &apps)
 	return
 }
+
+// ListCampaignsByAdvertiserProfileIterator will return an iterator over all campaigns (see ListCampaignsByAdvertiserProfile)
+// Pages are fetched lazily as the iterator advances and it stops after the last page.
+func (c *Client) ListCampaignsByAdvertiserProfileIterator(ctx context.Context, profileID uint64, resultsPerPage int,
+	sortBy, sortOrder string, opts ...IteratorOps) *CampaignIterator {
+	return newIterator(ctx, resultsPerPage, func(ctx context.Context, page, resultsPerPage int) ([]*Campaign, pageInfo, error) {
+		campaigns, _, err := c.ListCampaignsByAdvertiserProfileWithContext(ctx, profileID, page, resultsPerPage, sortBy, sortOrder)
+		return resultsPage[*Campaign](campaigns, err)
+	}, opts...)
+}
+
+// ListAppsByAdvertiserProfileIterator will return an iterator over all apps (see ListAppsByAdvertiserProfile)
+// Pages are fetched lazily as the iterator advances and it stops after the last page.
+func (c *Client) ListAppsByAdvertiserProfileIterator(ctx context.Context, profileID uint64, resultsPerPage int,
+	sortBy, sortOrder string, opts ...IteratorOps) *AppIterator {
+	return newIterator(ctx, resultsPerPage, func(ctx context.Context, page, resultsPerPage int) ([]*App, pageInfo, error) {
+		apps, _, err := c.ListAppsByAdvertiserProfileWithContext(ctx, profileID, page, resultsPerPage, sortBy, sortOrder)
+		return resultsPage[*App](apps, err)
+	}, opts...)
+}
@@ -0,0 +1,274 @@
+package tonicpow
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rssFeed is the RSS 2.0 document (only the fields used for feed items)
+type rssFeed struct {
+	Items []struct {
+		Description string `xml:"description"`
+		Enclosures  []struct {
+			Type string `xml:"type,attr"`
+			URL  string `xml:"url,attr"`
+		} `xml:"enclosure"`
+		GUID    string `xml:"guid"`
+		Link    string `xml:"link"`
+		PubDate string `xml:"pubDate"`
+		Title   string `xml:"title"`
+	} `xml:"channel>item"`
+}
+
+// atomFeed is the Atom 1.0 document (only the fields used for feed items)
+//
+// The campaign id is read from the "tonicpow:campaign_id" extension (see: CampaignFeed)
+type atomFeed struct {
+	Entries []struct {
+		CampaignID string `xml:"https://tonicpow.com/feed campaign_id"`
+		Content    string `xml:"content"`
+		ID         string `xml:"id"`
+		Links      []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"link"`
+		Published string `xml:"published"`
+		Summary   string `xml:"summary"`
+		Title     string `xml:"title"`
+		Updated   string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// jsonFeed is the JSON Feed (1.0 or 1.1) document (only the fields used for feed items)
+type jsonFeed struct {
+	Items []struct {
+		BannerImage   string     `json:"banner_image"`
+		ContentHTML   string     `json:"content_html"`
+		ContentText   string     `json:"content_text"`
+		DateModified  string     `json:"date_modified"`
+		DatePublished string     `json:"date_published"`
+		ID            jsonFeedID `json:"id"`
+		Image         string     `json:"image"`
+		Summary       string     `json:"summary"`
+		Title         string     `json:"title"`
+		URL           string     `json:"url"`
+	} `json:"items"`
+}
+
+// jsonFeedID is the id of a JSON Feed item (a string, but numbers are accepted)
+type jsonFeedID string
+
+// UnmarshalJSON will unmarshal the id from a string or a number
+func (i *jsonFeedID) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*i = jsonFeedID(id)
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+	*i = jsonFeedID(number.String())
+	return nil
+}
+
+// rssDateFormats are the date formats found in RSS feeds (RFC 822 and friends)
+var rssDateFormats = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+}
+
+// ParseCampaignFeed will parse a campaigns feed (RSS, Atom or JSON Feed) into feed items
+//
+// The campaign slug is taken from the item link (/campaign/{slug}) and the
+// campaign id is set when the item id is numeric (or from the Atom campaign id extension)
+func ParseCampaignFeed(feedType FeedType, body []byte) (items []*FeedItem, err error) {
+	switch feedType {
+	case FeedTypeRSS:
+		items, err = parseRSSFeed(body)
+	case FeedTypeAtom:
+		items, err = parseAtomFeed(body)
+	case FeedTypeJSON:
+		items, err = parseJSONFeed(body)
+	default:
+		return nil, newInvalidFeedTypeError(feedType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s feed: %w", feedType, err)
+	}
+
+	// Fill in the campaign details
+	for _, item := range items {
+		item.CampaignSlug = campaignSlugFromLink(item.Link)
+		if len(item.CampaignSlug) == 0 {
+			item.CampaignSlug = campaignSlugFromLink(item.ID)
+		}
+		if item.CampaignID == 0 {
+			item.CampaignID, _ = strconv.ParseUint(item.ID, 10, 64)
+		}
+	}
+	return
+}
+
+// CampaignsFeedItems will fetch the campaigns feed and parse it into feed items
+//
+// See CampaignsFeed() and ParseCampaignFeed()
+func (c *Client) CampaignsFeedItems(feedType FeedType) (items []*FeedItem, response *StandardResponse, err error) {
+	return c.CampaignsFeedItemsWithContext(context.Background(), feedType)
+}
+
+// CampaignsFeedItemsWithContext is the same as CampaignsFeedItems, but uses the given context for the request
+func (c *Client) CampaignsFeedItemsWithContext(ctx context.Context,
+	feedType FeedType) (items []*FeedItem, response *StandardResponse, err error) {
+
+	// Do we know this feed type? (before firing the request)
+	if !isValidFeedType(feedType) {
+		err = newInvalidFeedTypeError(feedType)
+		return
+	}
+
+	if _, response, err = c.CampaignsFeedWithContext(ctx, feedType); err != nil {
+		return
+	}
+	items, err = ParseCampaignFeed(feedType, response.Body)
+	return
+}
+
+// isValidFeedType will return true if the feed type can be parsed
+func isValidFeedType(feedType FeedType) bool {
+	return feedType == FeedTypeAtom || feedType == FeedTypeJSON || feedType == FeedTypeRSS
+}
+
+// parseRSSFeed will parse an RSS 2.0 feed
+func parseRSSFeed(body []byte) ([]*FeedItem, error) {
+	var feed rssFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return nil, err
+	}
+	items := make([]*FeedItem, 0, len(feed.Items))
+	for _, i := range feed.Items {
+		item := &FeedItem{
+			Description: strings.TrimSpace(i.Description),
+			ID:          strings.TrimSpace(i.GUID),
+			Link:        strings.TrimSpace(i.Link),
+			Published:   parseFeedTime(i.PubDate, rssDateFormats...),
+			Title:       strings.TrimSpace(i.Title),
+		}
+		for _, enclosure := range i.Enclosures {
+			if isImageType(enclosure.Type) {
+				item.ImageURL = enclosure.URL
+				break
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// parseAtomFeed will parse an Atom 1.0 feed
+func parseAtomFeed(body []byte) ([]*FeedItem, error) {
+	var feed atomFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return nil, err
+	}
+	items := make([]*FeedItem, 0, len(feed.Entries))
+	for _, e := range feed.Entries {
+		item := &FeedItem{
+			Description: strings.TrimSpace(e.Summary),
+			ID:          strings.TrimSpace(e.ID),
+			Published:   parseFeedTime(e.Published, time.RFC3339),
+			Title:       strings.TrimSpace(e.Title),
+			Updated:     parseFeedTime(e.Updated, time.RFC3339),
+		}
+		item.CampaignID, _ = strconv.ParseUint(strings.TrimSpace(e.CampaignID), 10, 64)
+		if len(item.Description) == 0 {
+			item.Description = strings.TrimSpace(e.Content)
+		}
+		if item.Published.IsZero() {
+			item.Published = item.Updated
+		}
+		for _, link := range e.Links {
+			switch {
+			case link.Rel == "enclosure" && isImageType(link.Type):
+				item.ImageURL = link.Href
+			case (link.Rel == "" || link.Rel == "alternate") && len(item.Link) == 0:
+				item.Link = link.Href
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// parseJSONFeed will parse a JSON Feed (1.0 or 1.1)
+func parseJSONFeed(body []byte) ([]*FeedItem, error) {
+	var feed jsonFeed
+	if err := json.Unmarshal(body, &feed); err != nil {
+		return nil, err
+	}
+	items := make([]*FeedItem, 0, len(feed.Items))
+	for _, i := range feed.Items {
+		item := &FeedItem{
+			Description: i.Summary,
+			ID:          string(i.ID),
+			ImageURL:    i.Image,
+			Link:        i.URL,
+			Published:   parseFeedTime(i.DatePublished, time.RFC3339),
+			Title:       i.Title,
+			Updated:     parseFeedTime(i.DateModified, time.RFC3339),
+		}
+		if len(item.Description) == 0 {
+			item.Description = i.ContentText
+		}
+		if len(item.ImageURL) == 0 {
+			item.ImageURL = i.BannerImage
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// parseFeedTime will parse the time using the first matching format (zero time if none match)
+func parseFeedTime(value string, formats ...string) time.Time {
+	value = strings.TrimSpace(value)
+	if len(value) == 0 {
+		return time.Time{}
+	}
+	for _, format := range formats {
+		if t, err := time.Parse(format, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// isImageType will return true if the mime type is an image (or unknown)
+func isImageType(mimeType string) bool {
+	return len(mimeType) == 0 || strings.HasPrefix(mimeType, "image/")
+}
+
+// campaignSlugFromLink will return the slug from a campaign link (/campaign/{slug})
+func campaignSlugFromLink(link string) string {
+	path := link
+	if u, err := url.Parse(link); err == nil && len(u.Path) > 0 {
+		path = u.Path
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(segments) - 2; i >= 0; i-- {
+		if segments[i] == "campaign" || segments[i] == modelCampaign {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,170 @@
+package tonicpow
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseCampaignFeed will test the method ParseCampaignFeed()
+func TestParseCampaignFeed(t *testing.T) {
+	t.Parallel()
+
+	published := time.Date(2019, 9, 5, 1, 50, 6, 0, time.UTC)
+
+	t.Run("existing feeds", func(t *testing.T) {
+		for feedType, body := range map[FeedType]string{
+			FeedTypeRSS:  newTestCampaignFeedRSS(),
+			FeedTypeAtom: newTestCampaignFeedAtom(),
+			FeedTypeJSON: newTestCampaignFeedJSON(),
+		} {
+			items, err := ParseCampaignFeed(feedType, []byte(body))
+			assert.NoError(t, err, feedType)
+			if !assert.Len(t, items, 1, feedType) {
+				continue
+			}
+			assert.Equal(t, "TonicPow", items[0].Title, feedType)
+			assert.Equal(t, "https://tonicpow.com/campaign/tonicpow", items[0].Link, feedType)
+			assert.Equal(t, "tonicpow", items[0].CampaignSlug, feedType)
+			assert.Equal(t, "Earn BSV for sharing things you like. Offer BSV for sharing your product or service.",
+				items[0].Description, feedType)
+			assert.True(t, published.Equal(items[0].Published), feedType)
+		}
+	})
+
+	t.Run("rss images and ids", func(t *testing.T) {
+		items, err := ParseCampaignFeed(FeedTypeRSS, []byte(`<rss version="2.0"><channel><item>
+  <title>Campaign</title>
+  <link>https://tonicpow.com/campaign/my-slug?ref=feed</link>
+  <guid>23</guid>
+  <pubDate>Thu, 05 Sep 2019 01:50:06 GMT</pubDate>
+  <enclosure url="https://example.com/file.mp3" type="audio/mpeg" length="1"/>
+  <enclosure url="https://example.com/image.png" type="image/png" length="1"/>
+</item></channel></rss>`))
+		assert.NoError(t, err)
+		if assert.Len(t, items, 1) {
+			assert.Equal(t, testCampaignID, items[0].CampaignID)
+			assert.Equal(t, "my-slug", items[0].CampaignSlug)
+			assert.Equal(t, "https://example.com/image.png", items[0].ImageURL)
+			assert.True(t, published.Equal(items[0].Published))
+		}
+	})
+
+	t.Run("atom images and dates", func(t *testing.T) {
+		items, err := ParseCampaignFeed(FeedTypeAtom, []byte(`<feed xmlns="http://www.w3.org/2005/Atom"><entry>
+  <title>Campaign</title>
+  <id>tag:tonicpow.com,2019-09-05:/campaign/my-slug</id>
+  <published>2019-09-05T01:50:06Z</published>
+  <updated>2021-06-04T17:23:38Z</updated>
+  <content type="html">Content</content>
+  <link href="https://example.com/image.jpg" rel="enclosure" type="image/jpeg"></link>
+</entry></feed>`))
+		assert.NoError(t, err)
+		if assert.Len(t, items, 1) {
+			assert.Equal(t, "my-slug", items[0].CampaignSlug)
+			assert.Equal(t, "Content", items[0].Description)
+			assert.Equal(t, "https://example.com/image.jpg", items[0].ImageURL)
+			assert.Equal(t, "", items[0].Link)
+			assert.True(t, published.Equal(items[0].Published))
+			assert.Equal(t, 2021, items[0].Updated.Year())
+		}
+	})
+
+	t.Run("json feed 1.1 with numeric id", func(t *testing.T) {
+		items, err := ParseCampaignFeed(FeedTypeJSON, []byte(`{
+  "version": "https://jsonfeed.org/version/1.1",
+  "items": [{"id": 23, "url": "https://tonicpow.com/campaigns/my-slug", "content_text": "Text",
+    "banner_image": "https://example.com/banner.png", "date_modified": "2021-06-04T17:23:38Z"}]
+}`))
+		assert.NoError(t, err)
+		if assert.Len(t, items, 1) {
+			assert.Equal(t, testCampaignID, items[0].CampaignID)
+			assert.Equal(t, "23", items[0].ID)
+			assert.Equal(t, "my-slug", items[0].CampaignSlug)
+			assert.Equal(t, "Text", items[0].Description)
+			assert.Equal(t, "https://example.com/banner.png", items[0].ImageURL)
+			assert.True(t, items[0].Published.IsZero())
+			assert.Equal(t, 2021, items[0].Updated.Year())
+		}
+	})
+
+	t.Run("empty feed", func(t *testing.T) {
+		items, err := ParseCampaignFeed(FeedTypeRSS, []byte(`<rss version="2.0"><channel></channel></rss>`))
+		assert.NoError(t, err)
+		assert.Empty(t, items)
+	})
+
+	t.Run("invalid feeds", func(t *testing.T) {
+		_, err := ParseCampaignFeed(FeedTypeRSS, []byte(`<rss`))
+		assert.Error(t, err)
+
+		_, err = ParseCampaignFeed(FeedTypeAtom, []byte(`not xml`))
+		assert.Error(t, err)
+
+		_, err = ParseCampaignFeed(FeedTypeJSON, []byte(`{"items": [{"id": true}]}`))
+		assert.Error(t, err)
+
+		_, err = ParseCampaignFeed("csv", []byte(`a,b`))
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+// TestClient_CampaignsFeedItems will test the method CampaignsFeedItems()
+func TestClient_CampaignsFeedItems(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("campaigns feed items (success)", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/feed/?%s=%s", EnvironmentDevelopment.apiURL,
+			modelCampaign, fieldFeedType, FeedTypeRSS,
+		)
+		mockResponseFeed(endpoint, http.StatusOK, newTestCampaignFeedRSS())
+
+		var items []*FeedItem
+		var response *StandardResponse
+		items, response, err = client.CampaignsFeedItems(FeedTypeRSS)
+		assert.NoError(t, err)
+		assert.NotNil(t, response)
+		if assert.Len(t, items, 1) {
+			assert.Equal(t, "tonicpow", items[0].CampaignSlug)
+		}
+	})
+
+	t.Run("campaigns feed items (error)", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/feed/?%s=%s", EnvironmentDevelopment.apiURL,
+			modelCampaign, fieldFeedType, FeedTypeJSON,
+		)
+		mockResponseFeed(endpoint, http.StatusBadRequest, `{"code":400,"message":"bad request"}`)
+
+		var items []*FeedItem
+		items, _, err = client.CampaignsFeedItems(FeedTypeJSON)
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Nil(t, items)
+	})
+
+	t.Run("invalid feed type is not fetched", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		httpmock.Reset()
+
+		var items []*FeedItem
+		var response *StandardResponse
+		items, response, err = client.CampaignsFeedItems("xml")
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Nil(t, items)
+		assert.Nil(t, response)
+		assert.Equal(t, 0, httpmock.GetTotalCallCount())
+	})
+}
@@ -0,0 +1,463 @@
+package tonicpow
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Feed content types (by feed type)
+const (
+	contentTypeAtom     = "application/atom+xml; charset=utf-8"
+	contentTypeJSONFeed = "application/feed+json; charset=utf-8"
+	contentTypeRSS      = "application/rss+xml; charset=utf-8"
+)
+
+// Feed defaults & extensions
+const (
+	feedDefaultImageType = "image/jpeg"                       // Used when the image type is unknown
+	feedDefaultLink      = "https://tonicpow.com"             // Default home page of the feed
+	feedDefaultTitle     = "TonicPow"                         // Default title of the feed
+	feedExtensionXMLNS   = "https://tonicpow.com/feed"        // XML namespace for the RSS & Atom extension elements
+	feedJSONFeedVersion  = "https://jsonfeed.org/version/1.1" // JSON Feed version
+)
+
+// CampaignFeed is a feed of campaigns that can be rendered as RSS 2.0, Atom 1.0 or JSON Feed 1.1
+//
+// Each item includes the campaign images (as enclosures) and the pay per click rate & currency
+// (as "tonicpow:" elements in RSS & Atom, and the "_tonicpow" extension in JSON Feed)
+type CampaignFeed struct {
+	AuthorEmail string                   // (optional) Author email of the feed
+	AuthorName  string                   // (optional) Author name of the feed
+	Campaigns   []*Campaign              // Campaigns to include (in order)
+	Description string                   // (optional) Description of the feed
+	ItemLink    func(c *Campaign) string // (optional) Link for a campaign (default: {Link}/campaign/{slug})
+	Link        string                   // (optional) Home page of the feed (default: https://tonicpow.com)
+	Title       string                   // (optional) Title of the feed (default: TonicPow)
+	Updated     time.Time                // (optional) Last update of the feed (default: latest campaign time)
+}
+
+// Render will render the feed using the feed type
+func (f *CampaignFeed) Render(feedType FeedType) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := f.Write(&buf, feedType); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write will write the feed using the feed type
+func (f *CampaignFeed) Write(w io.Writer, feedType FeedType) error {
+	switch feedType {
+	case FeedTypeRSS:
+		return writeXMLFeed(w, f.rss())
+	case FeedTypeAtom:
+		return writeXMLFeed(w, f.atom())
+	case FeedTypeJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(f.jsonFeed())
+	default:
+		return newInvalidFeedTypeError(feedType)
+	}
+}
+
+// CampaignFeedHandler will return an http.Handler that serves the feed from the source
+//
+// The feed type is taken from the "feed_type" query parameter (like the API), using
+// the given feed type when it is not set
+func CampaignFeedHandler(feedType FeedType, source func(r *http.Request) (*CampaignFeed, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", http.MethodGet+", "+http.MethodHead)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		requested := feedType
+		if value := r.URL.Query().Get(fieldFeedType); len(value) > 0 {
+			requested = FeedType(strings.ToLower(value))
+		}
+		contentType, ok := feedContentType(requested)
+		if !ok {
+			http.Error(w, newInvalidFeedTypeError(requested).Error(), http.StatusBadRequest)
+			return
+		}
+
+		feed, err := source(r)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		var body []byte
+		if body, err = feed.Render(requested); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method != http.MethodHead {
+			_, _ = w.Write(body)
+		}
+	})
+}
+
+// feedContentType will return the content type for the feed type
+func feedContentType(feedType FeedType) (string, bool) {
+	switch feedType {
+	case FeedTypeRSS:
+		return contentTypeRSS, true
+	case FeedTypeAtom:
+		return contentTypeAtom, true
+	case FeedTypeJSON:
+		return contentTypeJSONFeed, true
+	default:
+		return "", false
+	}
+}
+
+// writeXMLFeed will write the xml header and the feed
+func writeXMLFeed(w io.Writer, feed interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// rssOutput is the RSS 2.0 document
+type rssOutput struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	XMLNS   string   `xml:"xmlns:tonicpow,attr"`
+	Channel struct {
+		Title          string          `xml:"title"`
+		Link           string          `xml:"link"`
+		Description    string          `xml:"description"`
+		ManagingEditor string          `xml:"managingEditor,omitempty"`
+		PubDate        string          `xml:"pubDate,omitempty"`
+		Items          []rssOutputItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+// rssOutputItem is an RSS 2.0 item
+type rssOutputItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description,omitempty"`
+	GUID        struct {
+		IsPermaLink bool   `xml:"isPermaLink,attr"`
+		Value       string `xml:",chardata"`
+	} `xml:"guid"`
+	PubDate   string `xml:"pubDate,omitempty"`
+	Enclosure *struct {
+		Length int    `xml:"length,attr"`
+		Type   string `xml:"type,attr"`
+		URL    string `xml:"url,attr"`
+	} `xml:"enclosure,omitempty"`
+	Currency        string `xml:"tonicpow:currency,omitempty"`
+	PayPerClickRate string `xml:"tonicpow:pay_per_click_rate"`
+}
+
+// rss will return the RSS 2.0 document
+func (f *CampaignFeed) rss() *rssOutput {
+	feed := &rssOutput{Version: "2.0", XMLNS: feedExtensionXMLNS}
+	feed.Channel.Title = f.title()
+	feed.Channel.Link = f.link()
+	feed.Channel.Description = f.Description
+	if len(f.AuthorEmail) > 0 {
+		feed.Channel.ManagingEditor = strings.TrimSpace(f.AuthorEmail + " (" + f.AuthorName + ")")
+	}
+	if updated := f.updated(); !updated.IsZero() {
+		feed.Channel.PubDate = updated.Format(time.RFC1123Z)
+	}
+	for _, campaign := range f.Campaigns {
+		item := rssOutputItem{
+			Currency:        campaign.Currency,
+			Description:     campaign.Description,
+			Link:            f.itemLink(campaign),
+			PayPerClickRate: formatFeedRate(campaign.PayPerClickRate),
+			Title:           campaign.Title,
+		}
+		item.GUID.Value = strconv.FormatUint(campaign.ID, 10)
+		if published := parseAPITime(campaign.CreatedAt); !published.IsZero() {
+			item.PubDate = published.Format(time.RFC1123Z)
+		}
+		if images := campaignFeedImages(campaign); len(images) > 0 {
+			item.Enclosure = &struct {
+				Length int    `xml:"length,attr"`
+				Type   string `xml:"type,attr"`
+				URL    string `xml:"url,attr"`
+			}{Type: images[0].MimeType, URL: images[0].URL}
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+	return feed
+}
+
+// atomOutput is the Atom 1.0 document
+type atomOutput struct {
+	XMLName  xml.Name          `xml:"feed"`
+	XMLNS    string            `xml:"xmlns,attr"`
+	XMLNSExt string            `xml:"xmlns:tonicpow,attr"`
+	Title    string            `xml:"title"`
+	ID       string            `xml:"id"`
+	Updated  string            `xml:"updated"`
+	Subtitle string            `xml:"subtitle,omitempty"`
+	Link     atomOutputLink    `xml:"link"`
+	Author   *atomOutputAuthor `xml:"author,omitempty"`
+	Entries  []atomOutputEntry `xml:"entry"`
+}
+
+// atomOutputAuthor is an Atom 1.0 author
+type atomOutputAuthor struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email,omitempty"`
+}
+
+// atomOutputLink is an Atom 1.0 link
+type atomOutputLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// atomOutputEntry is an Atom 1.0 entry
+type atomOutputEntry struct {
+	Title     string           `xml:"title"`
+	ID        string           `xml:"id"`
+	Updated   string           `xml:"updated"`
+	Published string           `xml:"published,omitempty"`
+	Links     []atomOutputLink `xml:"link"`
+	Summary   *struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"summary,omitempty"`
+	CampaignID      uint64 `xml:"tonicpow:campaign_id"`
+	Currency        string `xml:"tonicpow:currency,omitempty"`
+	PayPerClickRate string `xml:"tonicpow:pay_per_click_rate"`
+}
+
+// atom will return the Atom 1.0 document
+func (f *CampaignFeed) atom() *atomOutput {
+	updated := f.updated()
+	if updated.IsZero() {
+		updated = time.Now().UTC()
+	}
+	feed := &atomOutput{
+		ID:       f.link(),
+		Link:     atomOutputLink{Href: f.link()},
+		Subtitle: f.Description,
+		Title:    f.title(),
+		Updated:  updated.Format(time.RFC3339),
+		XMLNS:    "http://www.w3.org/2005/Atom",
+		XMLNSExt: feedExtensionXMLNS,
+	}
+	if len(f.AuthorName) > 0 || len(f.AuthorEmail) > 0 {
+		feed.Author = &atomOutputAuthor{Email: f.AuthorEmail, Name: f.AuthorName}
+	}
+	for _, campaign := range f.Campaigns {
+		link := f.itemLink(campaign)
+		entry := atomOutputEntry{
+			CampaignID:      campaign.ID,
+			Currency:        campaign.Currency,
+			ID:              link,
+			Links:           []atomOutputLink{{Href: link, Rel: "alternate"}},
+			PayPerClickRate: formatFeedRate(campaign.PayPerClickRate),
+			Title:           campaign.Title,
+		}
+		published, entryUpdated := parseAPITime(campaign.CreatedAt), campaignUpdated(campaign)
+		if entryUpdated.IsZero() {
+			entryUpdated = updated
+		}
+		entry.Updated = entryUpdated.Format(time.RFC3339)
+		if !published.IsZero() {
+			entry.Published = published.Format(time.RFC3339)
+		}
+		if len(campaign.Description) > 0 {
+			entry.Summary = &struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			}{Type: "html", Value: campaign.Description}
+		}
+		for _, image := range campaignFeedImages(campaign) {
+			entry.Links = append(entry.Links, atomOutputLink{Href: image.URL, Rel: "enclosure", Type: image.MimeType})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return feed
+}
+
+// jsonFeedOutput is the JSON Feed 1.1 document
+type jsonFeedOutput struct {
+	Version     string               `json:"version"`
+	Title       string               `json:"title"`
+	HomePageURL string               `json:"home_page_url"`
+	Description string               `json:"description,omitempty"`
+	Authors     []jsonFeedAuthor     `json:"authors,omitempty"`
+	Items       []jsonFeedOutputItem `json:"items"`
+}
+
+// jsonFeedAuthor is a JSON Feed 1.1 author
+type jsonFeedAuthor struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// jsonFeedOutputItem is a JSON Feed 1.1 item
+type jsonFeedOutputItem struct {
+	ID            string                   `json:"id"`
+	URL           string                   `json:"url"`
+	Title         string                   `json:"title"`
+	Summary       string                   `json:"summary,omitempty"`
+	ContentText   string                   `json:"content_text"`
+	Image         string                   `json:"image,omitempty"`
+	DatePublished string                   `json:"date_published,omitempty"`
+	DateModified  string                   `json:"date_modified,omitempty"`
+	Attachments   []jsonFeedAttachment     `json:"attachments,omitempty"`
+	Extension     jsonFeedCampaignMetadata `json:"_tonicpow"`
+}
+
+// jsonFeedAttachment is a JSON Feed 1.1 attachment
+type jsonFeedAttachment struct {
+	MimeType string `json:"mime_type"`
+	URL      string `json:"url"`
+}
+
+// jsonFeedCampaignMetadata is the campaign metadata (JSON Feed extension)
+type jsonFeedCampaignMetadata struct {
+	CampaignID      uint64  `json:"campaign_id"`
+	Currency        string  `json:"currency,omitempty"`
+	PayPerClickRate float64 `json:"pay_per_click_rate"`
+}
+
+// jsonFeed will return the JSON Feed 1.1 document
+func (f *CampaignFeed) jsonFeed() *jsonFeedOutput {
+	feed := &jsonFeedOutput{
+		Description: f.Description,
+		HomePageURL: f.link(),
+		Items:       make([]jsonFeedOutputItem, 0, len(f.Campaigns)),
+		Title:       f.title(),
+		Version:     feedJSONFeedVersion,
+	}
+	if len(f.AuthorName) > 0 {
+		feed.Authors = []jsonFeedAuthor{{Name: f.AuthorName}}
+	}
+	for _, campaign := range f.Campaigns {
+		item := jsonFeedOutputItem{
+			ContentText: campaign.Description,
+			Extension: jsonFeedCampaignMetadata{
+				CampaignID:      campaign.ID,
+				Currency:        campaign.Currency,
+				PayPerClickRate: campaign.PayPerClickRate,
+			},
+			ID:      strconv.FormatUint(campaign.ID, 10),
+			Summary: campaign.Description,
+			Title:   campaign.Title,
+			URL:     f.itemLink(campaign),
+		}
+		if published := parseAPITime(campaign.CreatedAt); !published.IsZero() {
+			item.DatePublished = published.Format(time.RFC3339)
+		}
+		if updated := campaignUpdated(campaign); !updated.IsZero() {
+			item.DateModified = updated.Format(time.RFC3339)
+		}
+		for i, image := range campaignFeedImages(campaign) {
+			if i == 0 {
+				item.Image = image.URL
+			}
+			item.Attachments = append(item.Attachments, jsonFeedAttachment{MimeType: image.MimeType, URL: image.URL})
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return feed
+}
+
+// title will return the title of the feed
+func (f *CampaignFeed) title() string {
+	if len(f.Title) > 0 {
+		return f.Title
+	}
+	return feedDefaultTitle
+}
+
+// link will return the home page of the feed
+func (f *CampaignFeed) link() string {
+	if len(f.Link) > 0 {
+		return f.Link
+	}
+	return feedDefaultLink
+}
+
+// itemLink will return the link for the campaign
+func (f *CampaignFeed) itemLink(campaign *Campaign) string {
+	if f.ItemLink != nil {
+		return f.ItemLink(campaign)
+	}
+	return strings.TrimSuffix(f.link(), "/") + "/campaign/" + campaign.Slug
+}
+
+// updated will return the last update of the feed (or the latest campaign time)
+func (f *CampaignFeed) updated() (updated time.Time) {
+	if !f.Updated.IsZero() {
+		return f.Updated.UTC()
+	}
+	for _, campaign := range f.Campaigns {
+		for _, t := range []time.Time{parseAPITime(campaign.CreatedAt), campaignUpdated(campaign)} {
+			if t.After(updated) {
+				updated = t
+			}
+		}
+	}
+	return
+}
+
+// campaignUpdated will return the last event time of the campaign
+func campaignUpdated(campaign *Campaign) time.Time {
+	return parseAPITime(campaign.LastEventAt)
+}
+
+// campaignFeedImages will return the images of the campaign (with mime types)
+func campaignFeedImages(campaign *Campaign) []*CampaignImage {
+	images := make([]*CampaignImage, 0, len(campaign.Images)+1)
+	for _, image := range campaign.Images {
+		if image == nil || len(image.URL) == 0 {
+			continue
+		}
+		if len(image.MimeType) == 0 {
+			image = &CampaignImage{Height: image.Height, MimeType: imageMimeType(image.URL), URL: image.URL, Width: image.Width}
+		}
+		images = append(images, image)
+	}
+	if len(images) == 0 && len(campaign.ImageURL) > 0 {
+		images = append(images, &CampaignImage{MimeType: imageMimeType(campaign.ImageURL), URL: campaign.ImageURL})
+	}
+	return images
+}
+
+// imageMimeType will return the mime type of the image using the file extension
+func imageMimeType(imageURL string) string {
+	if mimeType := mime.TypeByExtension(path.Ext(strings.SplitN(imageURL, "?", 2)[0])); strings.HasPrefix(mimeType, "image/") {
+		return mimeType
+	}
+	return feedDefaultImageType
+}
+
+// formatFeedRate will format the rate without trailing zeros
+func formatFeedRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
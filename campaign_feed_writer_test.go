@@ -0,0 +1,175 @@
+package tonicpow
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestCampaignFeed will return a dummy feed for tests
+func newTestCampaignFeed() *CampaignFeed {
+	campaign := newTestCampaign()
+	campaign.Description = "Earn <b>BSV</b> & more"
+	campaign.LastEventAt = "2021-06-04 17:23:38"
+	campaign.PayPerClickRate = 0.05
+
+	noImages := newTestCampaign()
+	noImages.ID = 24
+	noImages.Slug = "no-images"
+	noImages.Images = nil
+	noImages.ImageURL = ""
+
+	return &CampaignFeed{
+		AuthorEmail: "support@tonicpow.com",
+		AuthorName:  "tonicpow",
+		Campaigns:   []*Campaign{campaign, noImages},
+		Description: "Curated campaigns",
+		Title:       "Partner Feed",
+	}
+}
+
+// TestCampaignFeed_Render will test the method Render()
+func TestCampaignFeed_Render(t *testing.T) {
+	t.Parallel()
+
+	published := time.Date(2021, 1, 1, 0, 0, 1, 0, time.UTC)
+
+	t.Run("round trip through the parser", func(t *testing.T) {
+		for _, feedType := range []FeedType{FeedTypeRSS, FeedTypeAtom, FeedTypeJSON} {
+			body, err := newTestCampaignFeed().Render(feedType)
+			assert.NoError(t, err, feedType)
+
+			var items []*FeedItem
+			items, err = ParseCampaignFeed(feedType, body)
+			assert.NoError(t, err, feedType)
+			if !assert.Len(t, items, 2, feedType) {
+				continue
+			}
+			assert.Equal(t, "TonicPow", items[0].Title, feedType)
+			assert.Equal(t, "https://tonicpow.com/campaign/tonicpow", items[0].Link, feedType)
+			assert.Equal(t, "tonicpow", items[0].CampaignSlug, feedType)
+			assert.Equal(t, "Earn <b>BSV</b> & more", items[0].Description, feedType)
+			assert.Equal(t, newTestCampaignImages().URL, items[0].ImageURL, feedType)
+			assert.True(t, published.Equal(items[0].Published), feedType)
+			assert.Equal(t, "no-images", items[1].CampaignSlug, feedType)
+			assert.Empty(t, items[1].ImageURL, feedType)
+			assert.Equal(t, testCampaignID, items[0].CampaignID, feedType)
+			assert.Equal(t, uint64(24), items[1].CampaignID, feedType)
+		}
+	})
+
+	t.Run("rss metadata", func(t *testing.T) {
+		body, err := newTestCampaignFeed().Render(FeedTypeRSS)
+		assert.NoError(t, err)
+		rss := string(body)
+		assert.True(t, strings.HasPrefix(rss, `<?xml version="1.0" encoding="UTF-8"?>`))
+		assert.Contains(t, rss, `xmlns:tonicpow="https://tonicpow.com/feed"`)
+		assert.Contains(t, rss, `<tonicpow:pay_per_click_rate>0.05</tonicpow:pay_per_click_rate>`)
+		assert.Contains(t, rss, `<tonicpow:currency>usd</tonicpow:currency>`)
+		assert.Contains(t, rss, `<enclosure length="0" type="`+newTestCampaignImages().MimeType+`"`)
+		assert.Contains(t, rss, `<pubDate>Fri, 04 Jun 2021 17:23:38 +0000</pubDate>`)
+		assert.Contains(t, rss, `<managingEditor>support@tonicpow.com (tonicpow)</managingEditor>`)
+	})
+
+	t.Run("atom metadata", func(t *testing.T) {
+		body, err := newTestCampaignFeed().Render(FeedTypeAtom)
+		assert.NoError(t, err)
+		atom := string(body)
+		assert.Contains(t, atom, `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:tonicpow="https://tonicpow.com/feed">`)
+		assert.Contains(t, atom, `<tonicpow:campaign_id>23</tonicpow:campaign_id>`)
+		assert.Contains(t, atom, `<tonicpow:pay_per_click_rate>0.05</tonicpow:pay_per_click_rate>`)
+		assert.Contains(t, atom, `rel="enclosure"`)
+		assert.Contains(t, atom, `<updated>2021-06-04T17:23:38Z</updated>`)
+	})
+
+	t.Run("json feed metadata", func(t *testing.T) {
+		body, err := newTestCampaignFeed().Render(FeedTypeJSON)
+		assert.NoError(t, err)
+
+		var feed map[string]interface{}
+		assert.NoError(t, json.Unmarshal(body, &feed))
+		assert.Equal(t, "https://jsonfeed.org/version/1.1", feed["version"])
+		assert.Equal(t, "Partner Feed", feed["title"])
+
+		items := feed["items"].([]interface{})
+		item := items[0].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{
+			"campaign_id":        float64(testCampaignID),
+			"currency":           "usd",
+			"pay_per_click_rate": 0.05,
+		}, item["_tonicpow"])
+		assert.Len(t, item["attachments"], 1)
+		assert.Equal(t, "2021-06-04T17:23:38Z", item["date_modified"])
+	})
+
+	t.Run("image url without images", func(t *testing.T) {
+		campaign := newTestCampaign()
+		campaign.Images = nil
+		campaign.ImageURL = "https://example.com/banner.png?v=1"
+		images := campaignFeedImages(campaign)
+		if assert.Len(t, images, 1) {
+			assert.Equal(t, "image/png", images[0].MimeType)
+		}
+	})
+
+	t.Run("custom item link", func(t *testing.T) {
+		feed := newTestCampaignFeed()
+		feed.ItemLink = func(c *Campaign) string { return c.TargetURL }
+		body, err := feed.Render(FeedTypeJSON)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), `"url": "https://tonicpow.com"`)
+	})
+
+	t.Run("invalid feed type", func(t *testing.T) {
+		body, err := newTestCampaignFeed().Render("csv")
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Nil(t, body)
+	})
+}
+
+// TestCampaignFeedHandler will test the method CampaignFeedHandler()
+func TestCampaignFeedHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := CampaignFeedHandler(FeedTypeRSS, func(_ *http.Request) (*CampaignFeed, error) {
+		return newTestCampaignFeed(), nil
+	})
+
+	tests := []struct {
+		method      string
+		target      string
+		status      int
+		contentType string
+	}{
+		{http.MethodGet, "/feed", http.StatusOK, "application/rss+xml; charset=utf-8"},
+		{http.MethodGet, "/feed?feed_type=atom", http.StatusOK, "application/atom+xml; charset=utf-8"},
+		{http.MethodGet, "/feed?feed_type=JSON", http.StatusOK, "application/feed+json; charset=utf-8"},
+		{http.MethodHead, "/feed", http.StatusOK, "application/rss+xml; charset=utf-8"},
+		{http.MethodGet, "/feed?feed_type=csv", http.StatusBadRequest, "text/plain; charset=utf-8"},
+		{http.MethodPost, "/feed", http.StatusMethodNotAllowed, "text/plain; charset=utf-8"},
+	}
+	for _, test := range tests {
+		t.Run(test.method+" "+test.target, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(test.method, test.target, nil))
+			assert.Equal(t, test.status, recorder.Code)
+			assert.Equal(t, test.contentType, recorder.Header().Get("Content-Type"))
+			if test.method == http.MethodHead {
+				assert.Empty(t, recorder.Body.String())
+			}
+		})
+	}
+
+	t.Run("source error", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		CampaignFeedHandler(FeedTypeJSON, func(_ *http.Request) (*CampaignFeed, error) {
+			return nil, assert.AnError
+		}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/feed", nil))
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+}
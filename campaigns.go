@@ -1,10 +1,11 @@
 package tonicpow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
+	"strconv"
 )
 
 // permitFields will remove fields that cannot be used
@@ -16,27 +17,33 @@ func (c *Campaign) permitFields() {
 //
 // For more information: https://docs.tonicpow.com/#b67e92bf-a481-44f6-a31d-26e6e0c521b1
 func (c *Client) CreateCampaign(campaign *Campaign) (*StandardResponse, error) {
+	return c.CreateCampaignWithContext(context.Background(), campaign)
+}
+
+// CreateCampaignWithContext is the same as CreateCampaign, but uses the given context for the request
+func (c *Client) CreateCampaignWithContext(ctx context.Context, campaign *Campaign) (*StandardResponse, error) {
 
 	// Basic requirements
 	if campaign.AdvertiserProfileID == 0 {
-		return nil, fmt.Errorf("missing required attribute: %s", fieldAdvertiserProfileID)
+		return nil, newMissingAttributeError(fieldAdvertiserProfileID)
 	} else if len(campaign.Title) == 0 {
-		return nil, fmt.Errorf("missing required attribute: %s", fieldTitle)
+		return nil, newMissingAttributeError(fieldTitle)
 	} else if len(campaign.Description) == 0 {
-		return nil, fmt.Errorf("missing required attribute: %s", fieldDescription)
+		return nil, newMissingAttributeError(fieldDescription)
 	} else if len(campaign.TargetType) == 0 {
-		return nil, fmt.Errorf("missing required attribute: %s", fieldTargetType)
+		return nil, newMissingAttributeError(fieldTargetType)
 	} else if campaign.TargetType == "url" && len(campaign.TargetURL) == 0 {
-		return nil, fmt.Errorf("missing required attribute: %s", fieldTargetURL)
+		return nil, newMissingAttributeError(fieldTargetURL)
 	} else if campaign.TargetType == "hosted" && len(campaign.TargetData) == 0 {
-		return nil, fmt.Errorf("missing required attribute: %s", fieldTargetData)
+		return nil, newMissingAttributeError(fieldTargetData)
 	}
 
 	// Fire the Request
+	ctx = withOperation(ctx, Operation{Name: "CreateCampaign", AdvertiserProfileID: campaign.AdvertiserProfileID})
 	var response *StandardResponse
 	var err error
-	if response, err = c.Request(
-		http.MethodPost,
+	if response, err = c.RequestWithContext(
+		ctx, http.MethodPost,
 		"/"+modelCampaign,
 		campaign, http.StatusCreated,
 	); err != nil {
@@ -53,16 +60,23 @@ func (c *Client) CreateCampaign(campaign *Campaign) (*StandardResponse, error) {
 // For more information: https://docs.tonicpow.com/#b827446b-be34-4678-b347-33c4f63dbf9e
 func (c *Client) GetCampaign(campaignID uint64) (campaign *Campaign,
 	response *StandardResponse, err error) {
+	return c.GetCampaignWithContext(context.Background(), campaignID)
+}
+
+// GetCampaignWithContext is the same as GetCampaign, but uses the given context for the request
+func (c *Client) GetCampaignWithContext(ctx context.Context, campaignID uint64) (campaign *Campaign,
+	response *StandardResponse, err error) {
 
 	// Must have an ID
 	if campaignID == 0 {
-		err = fmt.Errorf("missing required attribute: %s", fieldID)
+		err = newMissingAttributeError(fieldID)
 		return
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
+	ctx = withOperation(ctx, Operation{Name: "GetCampaign", CampaignID: campaignID})
+	if response, err = c.RequestWithContext(
+		ctx, http.MethodGet,
 		fmt.Sprintf("/%s/details/?%s=%d", modelCampaign, fieldID, campaignID),
 		nil, http.StatusOK,
 	); err != nil {
@@ -80,16 +94,23 @@ func (c *Client) GetCampaign(campaignID uint64) (campaign *Campaign,
 // For more information: https://docs.tonicpow.com/#b827446b-be34-4678-b347-33c4f63dbf9e
 func (c *Client) GetCampaignBySlug(slug string) (campaign *Campaign,
 	response *StandardResponse, err error) {
+	return c.GetCampaignBySlugWithContext(context.Background(), slug)
+}
+
+// GetCampaignBySlugWithContext is the same as GetCampaignBySlug, but uses the given context for the request
+func (c *Client) GetCampaignBySlugWithContext(ctx context.Context, slug string) (campaign *Campaign,
+	response *StandardResponse, err error) {
 
 	// Must have a slug
 	if len(slug) == 0 {
-		err = fmt.Errorf("missing required attribute: %s", fieldSlug)
+		err = newMissingAttributeError(fieldSlug)
 		return
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
+	ctx = withOperation(ctx, Operation{Name: "GetCampaignBySlug"})
+	if response, err = c.RequestWithContext(
+		ctx, http.MethodGet,
 		fmt.Sprintf("/%s/details/?%s=%s", modelCampaign, fieldSlug, slug),
 		nil, http.StatusOK,
 	); err != nil {
@@ -105,10 +126,15 @@ func (c *Client) GetCampaignBySlug(slug string) (campaign *Campaign,
 //
 // For more information: https://docs.tonicpow.com/#665eefd6-da42-4ca9-853c-fd8ca1bf66b2
 func (c *Client) UpdateCampaign(campaign *Campaign) (response *StandardResponse, err error) {
+	return c.UpdateCampaignWithContext(context.Background(), campaign)
+}
+
+// UpdateCampaignWithContext is the same as UpdateCampaign, but uses the given context for the request
+func (c *Client) UpdateCampaignWithContext(ctx context.Context, campaign *Campaign) (response *StandardResponse, err error) {
 
 	// Basic requirements
 	if campaign.ID == 0 {
-		err = fmt.Errorf("missing required attribute: %s", fieldID)
+		err = newMissingAttributeError(fieldID)
 		return
 	}
 
@@ -116,8 +142,9 @@ func (c *Client) UpdateCampaign(campaign *Campaign) (response *StandardResponse,
 	campaign.permitFields()
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodPut,
+	ctx = withOperation(ctx, Operation{Name: "UpdateCampaign", CampaignID: campaign.ID})
+	if response, err = c.RequestWithContext(
+		ctx, http.MethodPut,
 		"/"+modelCampaign,
 		campaign, http.StatusOK,
 	); err != nil {
@@ -133,10 +160,16 @@ func (c *Client) UpdateCampaign(campaign *Campaign) (response *StandardResponse,
 //
 // For more information: https://docs.tonicpow.com/#b3fe69d3-24ba-4c2a-a485-affbb0a738de
 func (c *Client) CampaignsFeed(feedType FeedType) (feed string, response *StandardResponse, err error) {
+	return c.CampaignsFeedWithContext(context.Background(), feedType)
+}
+
+// CampaignsFeedWithContext is the same as CampaignsFeed, but uses the given context for the request
+func (c *Client) CampaignsFeedWithContext(ctx context.Context, feedType FeedType) (feed string, response *StandardResponse, err error) {
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
+	ctx = withOperation(ctx, Operation{Name: "CampaignsFeed"})
+	if response, err = c.RequestWithContext(
+		ctx, http.MethodGet,
 		fmt.Sprintf("/%s/feed/?%s=%s", modelCampaign, fieldFeedType, feedType),
 		nil, http.StatusOK,
 	); err != nil {
@@ -153,39 +186,51 @@ func (c *Client) CampaignsFeed(feedType FeedType) (feed string, response *Standa
 // For more information: https://docs.tonicpow.com/#c1b17be6-cb10-48b3-a519-4686961ff41c
 func (c *Client) ListCampaigns(page, resultsPerPage int, sortBy, sortOrder, searchQuery string,
 	minimumBalance uint64, includeExpired bool) (results *CampaignResults, response *StandardResponse, err error) {
+	return c.ListCampaignsWithContext(
+		context.Background(), page, resultsPerPage, sortBy, sortOrder,
+		searchQuery, minimumBalance, includeExpired,
+	)
+}
+
+// ListCampaignsWithContext is the same as ListCampaigns, but uses the given context for the request
+func (c *Client) ListCampaignsWithContext(ctx context.Context, page, resultsPerPage int, sortBy, sortOrder, searchQuery string,
+	minimumBalance uint64, includeExpired bool) (results *CampaignResults, response *StandardResponse, err error) {
 
-	// Do we know this field?
-	if len(sortBy) > 0 {
-		if !isInList(strings.ToLower(sortBy), campaignSortFields) {
-			err = fmt.Errorf("sort by %s is not valid", sortBy)
-			return
-		}
-	} else {
-		sortBy = SortByFieldCreatedAt
-		sortOrder = SortOrderDesc
+	// Do we know these fields?
+	if err = validateList(page, resultsPerPage, sortBy, sortOrder, campaignSortFields); err != nil {
+		return
 	}
+	sortBy, sortOrder = sortDefaults(sortBy, sortOrder)
 
-	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
-		fmt.Sprintf(
-			"/%s/list?%s=%d&%s=%d&%s=%s&%s=%s&%s=%s&%s=%d&%s=%t",
-			modelCampaign,
-			fieldCurrentPage, page,
-			fieldResultsPerPage, resultsPerPage,
-			fieldSortBy, sortBy,
-			fieldSortOrder, sortOrder,
-			fieldSearchQuery, searchQuery,
-			fieldMinimumBalance, minimumBalance,
-			fieldExpired, includeExpired,
-		),
-		nil, http.StatusOK,
-	); err != nil {
+	return c.listCampaigns(ctx, "ListCampaigns", orderedQuery(
+		fieldCurrentPage, strconv.Itoa(page),
+		fieldResultsPerPage, strconv.Itoa(resultsPerPage),
+		fieldSortBy, sortBy,
+		fieldSortOrder, sortOrder,
+		fieldSearchQuery, searchQuery,
+		fieldMinimumBalance, strconv.FormatUint(minimumBalance, 10),
+		fieldExpired, strconv.FormatBool(includeExpired),
+	))
+}
+
+// ListCampaignsWithQuery will return a list of campaigns using the query
+// Pagination & sorting defaults are applied (see ListCampaignsQuery)
+//
+// For more information: https://docs.tonicpow.com/#c1b17be6-cb10-48b3-a519-4686961ff41c
+func (c *Client) ListCampaignsWithQuery(ctx context.Context,
+	query ListCampaignsQuery) (results *CampaignResults, response *StandardResponse, err error) {
+
+	// Do we know these fields?
+	if err = query.Validate(); err != nil {
 		return
 	}
+	query = query.withDefaults()
 
-	err = json.Unmarshal(response.Body, &results)
-	return
+	operationName := "ListCampaigns"
+	if len(query.TargetURL) > 0 {
+		operationName = "ListCampaignsByURL"
+	}
+	return c.listCampaigns(ctx, operationName, query.Encode())
 }
 
 // ListCampaignsByURL will return a list of campaigns using the target url
@@ -194,35 +239,43 @@ func (c *Client) ListCampaigns(page, resultsPerPage int, sortBy, sortOrder, sear
 // For more information: https://docs.tonicpow.com/#30a15b69-7912-4e25-ba41-212529fba5ff
 func (c *Client) ListCampaignsByURL(targetURL string, page, resultsPerPage int,
 	sortBy, sortOrder string) (results *CampaignResults, response *StandardResponse, err error) {
+	return c.ListCampaignsByURLWithContext(context.Background(), targetURL, page, resultsPerPage, sortBy, sortOrder)
+}
+
+// ListCampaignsByURLWithContext is the same as ListCampaignsByURL, but uses the given context for the request
+func (c *Client) ListCampaignsByURLWithContext(ctx context.Context, targetURL string, page, resultsPerPage int,
+	sortBy, sortOrder string) (results *CampaignResults, response *StandardResponse, err error) {
 
 	// Must have a value
 	if len(targetURL) == 0 {
-		err = fmt.Errorf("missing required attribute: %s", fieldTargetURL)
+		err = newMissingAttributeError(fieldTargetURL)
 		return
 	}
 
-	// Do we know this field?
-	if len(sortBy) > 0 {
-		if !isInList(strings.ToLower(sortBy), campaignSortFields) {
-			err = fmt.Errorf("sort by %s is not valid", sortBy)
-			return
-		}
-	} else {
-		sortBy = SortByFieldCreatedAt
-		sortOrder = SortOrderDesc
+	// Do we know these fields?
+	if err = validateList(page, resultsPerPage, sortBy, sortOrder, campaignSortFields); err != nil {
+		return
 	}
+	sortBy, sortOrder = sortDefaults(sortBy, sortOrder)
+
+	return c.listCampaigns(ctx, "ListCampaignsByURL", orderedQuery(
+		fieldTargetURL, targetURL,
+		fieldCurrentPage, strconv.Itoa(page),
+		fieldResultsPerPage, strconv.Itoa(resultsPerPage),
+		fieldSortBy, sortBy,
+		fieldSortOrder, sortOrder,
+	))
+}
+
+// listCampaigns will fire the list request using the encoded query
+func (c *Client) listCampaigns(ctx context.Context, operationName,
+	query string) (results *CampaignResults, response *StandardResponse, err error) {
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
-		fmt.Sprintf("/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
-			modelCampaign,
-			fieldTargetURL, targetURL,
-			fieldCurrentPage, page,
-			fieldResultsPerPage, resultsPerPage,
-			fieldSortBy, sortBy,
-			fieldSortOrder, sortOrder,
-		),
+	ctx = withOperation(ctx, Operation{Name: operationName})
+	if response, err = c.RequestWithContext(
+		ctx, http.MethodGet,
+		"/"+modelCampaign+"/list?"+query,
 		nil, http.StatusOK,
 	); err != nil {
 		return
@@ -231,3 +284,26 @@ func (c *Client) ListCampaignsByURL(targetURL string, page, resultsPerPage int,
 	err = json.Unmarshal(response.Body, &results)
 	return
 }
+
+// ListCampaignsIterator will return an iterator over all campaigns (see ListCampaigns)
+// Pages are fetched lazily as the iterator advances and it stops after the last page.
+func (c *Client) ListCampaignsIterator(ctx context.Context, resultsPerPage int, sortBy, sortOrder, searchQuery string,
+	minimumBalance uint64, includeExpired bool, opts ...IteratorOps) *CampaignIterator {
+	return newIterator(ctx, resultsPerPage, func(ctx context.Context, page, resultsPerPage int) ([]*Campaign, pageInfo, error) {
+		results, _, err := c.ListCampaignsWithContext(
+			ctx, page, resultsPerPage, sortBy, sortOrder,
+			searchQuery, minimumBalance, includeExpired,
+		)
+		return resultsPage[*Campaign](results, err)
+	}, opts...)
+}
+
+// ListCampaignsByURLIterator will return an iterator over all campaigns using the target url (see ListCampaignsByURL)
+// Pages are fetched lazily as the iterator advances and it stops after the last page.
+func (c *Client) ListCampaignsByURLIterator(ctx context.Context, targetURL string, resultsPerPage int,
+	sortBy, sortOrder string, opts ...IteratorOps) *CampaignIterator {
+	return newIterator(ctx, resultsPerPage, func(ctx context.Context, page, resultsPerPage int) ([]*Campaign, pageInfo, error) {
+		results, _, err := c.ListCampaignsByURLWithContext(ctx, targetURL, page, resultsPerPage, sortBy, sortOrder)
+		return resultsPage[*Campaign](results, err)
+	}, opts...)
+}
@@ -1,9 +1,12 @@
 package tonicpow
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -359,6 +362,60 @@ func TestClient_GetCampaign(t *testing.T) {
 		assert.Equal(t, testCampaignID, newCampaign.ID)
 	})
 
+	t.Run("get a campaign with context (success)", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		campaign := newTestCampaign()
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/details/?%s=%d", EnvironmentDevelopment.apiURL,
+			modelCampaign, fieldID, campaign.ID,
+		)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, campaign)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var newCampaign *Campaign
+		var response *StandardResponse
+		newCampaign, response, err = client.GetCampaignWithContext(ctx, campaign.ID)
+		assert.NoError(t, err)
+		assert.NotNil(t, newCampaign)
+		assert.NotNil(t, response)
+		assert.Equal(t, testCampaignID, newCampaign.ID)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		campaign := newTestCampaign()
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/details/?%s=%d", EnvironmentDevelopment.apiURL,
+			modelCampaign, fieldID, campaign.ID,
+		)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, campaign)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var newCampaign *Campaign
+		var response *StandardResponse
+		newCampaign, response, err = client.GetCampaignWithContext(ctx, campaign.ID)
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, newCampaign)
+		assert.Nil(t, response)
+	})
+
 	t.Run("missing campaign id", func(t *testing.T) {
 		client, err := newTestClient()
 		assert.NoError(t, err)
@@ -975,7 +1032,7 @@ func TestClient_ListCampaignsByURL(t *testing.T) {
 
 		endpoint := fmt.Sprintf("%s/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
 			EnvironmentDevelopment.apiURL, modelCampaign,
-			fieldTargetURL, testCampaignTargetURL,
+			fieldTargetURL, url.QueryEscape(testCampaignTargetURL),
 			fieldCurrentPage, 1,
 			fieldResultsPerPage, 25,
 			fieldSortBy, SortByFieldBalance,
@@ -1003,7 +1060,7 @@ func TestClient_ListCampaignsByURL(t *testing.T) {
 
 		endpoint := fmt.Sprintf("%s/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
 			EnvironmentDevelopment.apiURL, modelCampaign,
-			fieldTargetURL, testCampaignTargetURL,
+			fieldTargetURL, url.QueryEscape(testCampaignTargetURL),
 			fieldCurrentPage, 2,
 			fieldResultsPerPage, 5,
 			fieldSortBy, SortByFieldCreatedAt,
@@ -1031,7 +1088,7 @@ func TestClient_ListCampaignsByURL(t *testing.T) {
 
 		endpoint := fmt.Sprintf("%s/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
 			EnvironmentDevelopment.apiURL, modelCampaign,
-			fieldTargetURL, testCampaignTargetURL,
+			fieldTargetURL, url.QueryEscape(testCampaignTargetURL),
 			fieldCurrentPage, 2,
 			fieldResultsPerPage, 5,
 			fieldSortBy, SortByFieldCreatedAt,
@@ -1058,7 +1115,7 @@ func TestClient_ListCampaignsByURL(t *testing.T) {
 
 		endpoint := fmt.Sprintf("%s/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
 			EnvironmentDevelopment.apiURL, modelCampaign,
-			fieldTargetURL, testCampaignTargetURL,
+			fieldTargetURL, url.QueryEscape(testCampaignTargetURL),
 			fieldCurrentPage, 2,
 			fieldResultsPerPage, 5,
 			fieldSortBy, SortByFieldCreatedAt,
@@ -1085,7 +1142,7 @@ func TestClient_ListCampaignsByURL(t *testing.T) {
 
 		endpoint := fmt.Sprintf("%s/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
 			EnvironmentDevelopment.apiURL, modelCampaign,
-			fieldTargetURL, testCampaignTargetURL,
+			fieldTargetURL, url.QueryEscape(testCampaignTargetURL),
 			fieldCurrentPage, 2,
 			fieldResultsPerPage, 5,
 			fieldSortBy, SortByFieldCreatedAt,
@@ -1110,7 +1167,7 @@ func TestClient_ListCampaignsByURL(t *testing.T) {
 
 		endpoint := fmt.Sprintf("%s/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
 			EnvironmentDevelopment.apiURL, modelCampaign,
-			fieldTargetURL, testCampaignTargetURL,
+			fieldTargetURL, url.QueryEscape(testCampaignTargetURL),
 			fieldCurrentPage, 2,
 			fieldResultsPerPage, 5,
 			fieldSortBy, SortByFieldCreatedAt,
@@ -1,8 +1,10 @@
 package tonicpow
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
@@ -13,27 +15,38 @@ import (
 type (
 	// Client is the TonicPow client/configuration
 	Client struct {
-		httpClient *resty.Client
-		options    *ClientOptions // Options are all the default settings / configuration
+		httpClient  *resty.Client
+		options     *ClientOptions // Options are all the default settings / configuration
+		rateLimiter *RateLimiter   // Rate limiter for outgoing requests (if set)
 	}
 
 	// ClientOptions holds all the configuration for client requests and default resources
 	ClientOptions struct {
-		apiKey         string              // API key
-		env            Environment         // Environment
-		customHeaders  map[string][]string // Custom headers on outgoing requests
-		httpTimeout    time.Duration       // Default timeout in seconds for GET requests
-		requestTracing bool                // If enabled, it will trace the request timing
-		retryCount     int                 // Default retry count for HTTP requests
-		userAgent      string              // User agent for all outgoing requests
+		apiKey                  string              // API key
+		callMiddleware          []Middleware        // Middleware chain around every API call (all attempts)
+		env                     Environment         // Environment
+		customHeaders           map[string][]string // Custom headers on outgoing requests
+		httpTimeout             time.Duration       // Default timeout in seconds for GET requests
+		idempotencyKeyGenerator func() string       // Generates idempotency keys for write requests (if set)
+		logger                  *slog.Logger        // Logger for requests, retries and failures (disabled if nil)
+		logLevel                slog.Level          // Minimum level the client will log
+		middleware              []Middleware        // Middleware chain around every request (first is outermost)
+		rateLimit               float64             // Requests per second (rate limiter is disabled if 0)
+		rateLimitBurst          int                 // Maximum requests in a burst (rate limiter)
+		requestTracing          bool                // If enabled, it will trace the request timing
+		retryCount              int                 // Default retry count for HTTP requests
+		retryPolicy             *RetryPolicy        // Retry policy (replaces the retry count if set)
+		userAgent               string              // User agent for all outgoing requests
 	}
 
 	// StandardResponse is the standard fields returned on all responses
 	StandardResponse struct {
-		Body       []byte          `json:"-"` // Body of the response request
-		Error      *Error          `json:"-"` // API error response
-		StatusCode int             `json:"-"` // Status code returned on the request
-		Tracing    resty.TraceInfo `json:"-"` // Trace information if enabled on the request
+		Body           []byte          `json:"-"` // Body of the response request
+		Error          *Error          `json:"-"` // API error response
+		Header         http.Header     `json:"-"` // Headers returned on the request
+		IdempotencyKey string          `json:"-"` // Idempotency key sent with the request (POST / PUT)
+		StatusCode     int             `json:"-"` // Status code returned on the request
+		Tracing        resty.TraceInfo `json:"-"` // Trace information if enabled on the request
 	}
 )
 
@@ -56,12 +69,20 @@ func NewClient(opts ...ClientOps) (ClientInterface, error) {
 	if client.options.apiKey == "" {
 		return nil, errors.New("missing an API Key")
 	}
+	// Set the rate limiter
+	if client.options.rateLimit > 0 {
+		client.rateLimiter = NewRateLimiter(client.options.rateLimit, client.options.rateLimitBurst)
+	}
 	// Set the Resty HTTP client
 	if client.httpClient == nil {
 		client.httpClient = resty.New()
 		// Set defaults (for GET requests)
 		client.httpClient.SetTimeout(client.options.httpTimeout)
-		client.httpClient.SetRetryCount(client.options.retryCount)
+		if client.options.retryPolicy != nil {
+			client.httpClient.SetRetryCount(0) // Retries are handled by the RetryPolicy
+		} else {
+			client.httpClient.SetRetryCount(client.options.retryCount)
+		}
 	}
 	return client, nil
 }
@@ -82,6 +103,11 @@ func (c *Client) GetEnvironment() Environment {
 	return c.options.env
 }
 
+// RateLimiter will return the client's rate limiter (nil if not set)
+func (c *Client) RateLimiter() *RateLimiter {
+	return c.rateLimiter
+}
+
 // Options will return the clients current options
 func (c *Client) Options() *ClientOptions {
 	return c.options
@@ -91,74 +117,202 @@ func (c *Client) Options() *ClientOptions {
 // Omit the data attribute if using a GET request
 func (c *Client) Request(httpMethod string, requestEndpoint string,
 	data interface{}, expectedCode int) (response *StandardResponse, err error) {
+	return c.RequestWithContext(context.Background(), httpMethod, requestEndpoint, data, expectedCode)
+}
 
-	// Set the user agent
-	req := c.httpClient.R().SetHeader("User-Agent", c.options.userAgent)
+// RequestWithContext is the same as Request, but the given context is attached to the
+// outgoing HTTP request (cancellation and deadlines are honored)
+func (c *Client) RequestWithContext(ctx context.Context, httpMethod string, requestEndpoint string,
+	data interface{}, expectedCode int) (response *StandardResponse, err error) {
+
+	// Do not fire the request if the context is already done
+	if err = ctx.Err(); err != nil {
+		return
+	}
 
 	// Set the body if (PUT || POST)
+	var body []byte
 	if httpMethod != http.MethodGet && httpMethod != http.MethodDelete {
-		var j []byte
-		if j, err = json.Marshal(data); err != nil {
+		if body, err = json.Marshal(data); err != nil {
 			return
 		}
-		req = req.SetBody(string(j))
-		req.Header.Add("Content-Length", strconv.Itoa(len(j)))
-		req.Header.Set("Content-Type", "application/json")
 	}
 
-	// Enable tracing
-	if c.options.requestTracing {
-		req.EnableTrace()
+	// The same idempotency key is used on every attempt
+	apiRequest := c.newAPIRequest(httpMethod, requestEndpoint, body, c.idempotencyKey(ctx, httpMethod), expectedCode)
+	apiRequest.Operation = operationFromContext(ctx)
+
+	// Wrap the whole call (every attempt) with the call middleware
+	return chainMiddleware(c.fireAttempts, c.options.callMiddleware)(ctx, apiRequest)
+}
+
+// fireAttempts will fire the request (retry using the policy if set) and wait for the rate limiter
+func (c *Client) fireAttempts(ctx context.Context, callRequest *APIRequest) (response *StandardResponse, err error) {
+
+	// Wrap each attempt with the middleware
+	roundTrip := chainMiddleware(c.fireRequest, c.options.middleware)
+	idempotencyKey := callRequest.Header.Get(headerIdempotencyKey)
+	operation := callRequest.Operation
+
+	// Fire the request (retry using the policy if set)
+	policy := c.options.retryPolicy
+	for attempt := 1; ; attempt++ {
+
+		// Wait for the rate limiter
+		if c.rateLimiter != nil {
+			if err = c.rateLimiter.Wait(ctx); err != nil {
+				return
+			}
+		}
+
+		apiRequest := callRequest.forAttempt(attempt)
+		start := time.Now()
+		response, err = roundTrip(ctx, apiRequest)
+		c.logResponse(ctx, apiRequest, attempt, response, err, time.Since(start))
+
+		// Adapt the rate limiter to the API limits
+		if c.rateLimiter != nil && response != nil {
+			c.rateLimiter.observe(response.StatusCode, response.Header)
+		}
+
+		if policy == nil || !policy.shouldRetry(
+			policy.isRetryableMethod(callRequest.Method) || len(idempotencyKey) > 0, attempt, response, err,
+		) {
+			return
+		}
+
+		// Calculate the delay (Retry-After is honored)
+		retry := RetryInfo{
+			Attempt:  attempt,
+			Endpoint: callRequest.Endpoint,
+			Err:      err,
+			Method:   callRequest.Method,
+		}
+		var retryAfter time.Duration
+		if response != nil {
+			retry.StatusCode = response.StatusCode
+			retryAfter = parseRetryAfter(response.Header)
+		}
+		retry.Delay = policy.delay(attempt, retryAfter)
+
+		// Report the retry
+		c.logRetry(ctx, retry, operation.Name)
+		if policy.OnRetry != nil {
+			policy.OnRetry(retry)
+		}
+
+		// Wait for the next attempt
+		if waitErr := sleepContext(ctx, retry.Delay); waitErr != nil {
+			return response, waitErr
+		}
+	}
+}
+
+// newAPIRequest will create a new APIRequest with the default headers
+func (c *Client) newAPIRequest(httpMethod string, requestEndpoint string,
+	body []byte, idempotencyKey string, expectedCode int) *APIRequest {
+
+	// Set the user agent
+	header := http.Header{}
+	header.Set("User-Agent", c.options.userAgent)
+
+	// Set the content type if (PUT || POST)
+	if body != nil {
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+		header.Set("Content-Type", "application/json")
 	}
 
-	// Set the authorization and content type
-	req.Header.Set(fieldAPIKey, c.options.apiKey)
+	// Set the authorization
+	header.Set(fieldAPIKey, c.options.apiKey)
 
 	// Custom headers?
 	for key, headers := range c.options.customHeaders {
 		for _, value := range headers {
-			req.Header.Set(key, value)
+			header.Set(key, value)
+		}
+	}
+
+	// Idempotency key?
+	if len(idempotencyKey) > 0 {
+		header.Set(headerIdempotencyKey, idempotencyKey)
+	}
+
+	return &APIRequest{
+		Body:         body,
+		Endpoint:     requestEndpoint,
+		ExpectedCode: expectedCode,
+		Header:       header,
+		Method:       httpMethod,
+	}
+}
+
+// fireRequest will fire a single HTTP request and parse the response (end of the middleware chain)
+func (c *Client) fireRequest(ctx context.Context, apiRequest *APIRequest) (response *StandardResponse, err error) {
+
+	// Set the context & headers
+	req := c.httpClient.R().SetContext(ctx)
+	for key, values := range apiRequest.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
 		}
 	}
 
+	// Set the body if (PUT || POST)
+	if apiRequest.Body != nil {
+		req = req.SetBody(string(apiRequest.Body))
+	}
+
+	// Enable tracing
+	if c.options.requestTracing {
+		req.EnableTrace()
+	}
+
 	// Fire the request
 	var resp *resty.Response
-	switch httpMethod {
+	requestURL := c.options.env.URL() + apiRequest.Endpoint
+	switch apiRequest.Method {
 	case http.MethodPost:
-		resp, err = req.Post(c.options.env.URL() + requestEndpoint)
+		resp, err = req.Post(requestURL)
 	case http.MethodPut:
-		resp, err = req.Put(c.options.env.URL() + requestEndpoint)
+		resp, err = req.Put(requestURL)
 	case http.MethodDelete:
-		resp, err = req.Delete(c.options.env.URL() + requestEndpoint)
+		resp, err = req.Delete(requestURL)
 	case http.MethodGet:
-		resp, err = req.Get(c.options.env.URL() + requestEndpoint)
+		resp, err = req.Get(requestURL)
 	}
 	if err != nil {
 		return
 	}
 
 	// Start the response
-	response = new(StandardResponse)
+	response = &StandardResponse{IdempotencyKey: apiRequest.Header.Get(headerIdempotencyKey)}
 
 	// Tracing enabled?
 	if c.options.requestTracing {
 		response.Tracing = resp.Request.TraceInfo()
 	}
 
-	// Set the status code & body
+	// Set the status code, headers & body
 	response.StatusCode = resp.StatusCode()
+	response.Header = resp.Header()
 	response.Body = resp.Body()
 
 	// Check expected code if set
-	if expectedCode > 0 && response.StatusCode != expectedCode {
-		response.Error = new(Error)
-		if err = json.Unmarshal(response.Body, &response.Error); err != nil {
-			return
-		}
-		err = errors.New(response.Error.Message)
+	if apiRequest.ExpectedCode > 0 && response.StatusCode != apiRequest.ExpectedCode {
 		if response.StatusCode == 0 { // If a 200 is expected, but you get a 201, this case occurs (improper status code check)
 			response.StatusCode = http.StatusInternalServerError
 		}
+
+		// Parse the API error (if the body is not a JSON error, the status code is still used)
+		response.Error = new(Error)
+		if jsonErr := json.Unmarshal(response.Body, response.Error); jsonErr != nil {
+			c.logDecodeError(ctx, apiRequest, response.StatusCode, jsonErr)
+			response.Error = new(Error)
+		}
+		if response.Error.StatusCode == 0 {
+			response.Error.StatusCode = response.StatusCode
+		}
+		err = response.Error
 	}
 
 	return
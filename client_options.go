@@ -1,6 +1,7 @@
 package tonicpow
 
 import (
+	"log/slog"
 	"strings"
 	"time"
 )
@@ -17,6 +18,7 @@ func defaultClientOptions() (opts *ClientOptions) {
 	opts = &ClientOptions{
 		env:            EnvironmentLive,
 		httpTimeout:    defaultHTTPTimeout,
+		logLevel:       slog.LevelInfo,
 		requestTracing: false,
 		retryCount:     defaultRetryCount,
 		userAgent:      defaultUserAgent,
@@ -60,6 +62,61 @@ func WithHTTPTimeout(timeout time.Duration) ClientOps {
 	}
 }
 
+// WithIdempotencyKeyGenerator will generate an idempotency key for every write request (POST / PUT)
+// that does not already have a key (see: NewIdempotencyKey, ContextWithIdempotencyKey)
+// The generated key is returned in StandardResponse.IdempotencyKey.
+// Keys are not generated by default.
+func WithIdempotencyKeyGenerator(generator func() string) ClientOps {
+	return func(c *ClientOptions) {
+		c.idempotencyKeyGenerator = generator
+	}
+}
+
+// WithLogger will log requests, retries and failures using the logger (the API key is redacted)
+// Successful requests are logged at debug, retries & client errors (4xx) at warn, and other failures at error.
+// Logging is disabled by default.
+func WithLogger(logger *slog.Logger) ClientOps {
+	return func(c *ClientOptions) {
+		c.logger = logger
+	}
+}
+
+// WithLogLevel will set the minimum level the client will log (IE: slog.LevelWarn for production)
+// Default level is info.
+func WithLogLevel(level slog.Level) ClientOps {
+	return func(c *ClientOptions) {
+		c.logLevel = level
+	}
+}
+
+// WithCallMiddleware will add middleware around every API call made through Client.Request
+// Call middleware runs once per call and wraps the rate limiter, every attempt and the retry delays.
+// Headers set by call middleware are sent on every attempt. No call middleware is set by default.
+func WithCallMiddleware(middleware ...Middleware) ClientOps {
+	return func(c *ClientOptions) {
+		c.callMiddleware = append(c.callMiddleware, middleware...)
+	}
+}
+
+// WithMiddleware will add middleware around every request made through Client.Request
+// Middleware runs in the order given (the first is the outermost) and can be supplied multiple times.
+// No middleware is set by default.
+func WithMiddleware(middleware ...Middleware) ClientOps {
+	return func(c *ClientOptions) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// WithRateLimit will limit outgoing requests using a token bucket (requests per second & burst)
+// The limiter pauses when the API responds with 429 or reports no remaining requests.
+// Rate limiting is disabled by default.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOps {
+	return func(c *ClientOptions) {
+		c.rateLimit = requestsPerSecond
+		c.rateLimitBurst = burst
+	}
+}
+
 // WithRequestTracing will enable tracing.
 // Tracing is disabled by default.
 func WithRequestTracing() ClientOps {
@@ -76,6 +133,15 @@ func WithRetryCount(retries int) ClientOps {
 	}
 }
 
+// WithRetryPolicy will set a retry policy for all requests (exponential backoff, jitter, Retry-After)
+// The policy replaces the default retry count (see: DefaultRetryPolicy)
+// Retry policy is not set by default.
+func WithRetryPolicy(policy RetryPolicy) ClientOps {
+	return func(c *ClientOptions) {
+		c.retryPolicy = &policy
+	}
+}
+
 // WithUserAgent will overwrite the default useragent.
 // Default is package name + version.
 func WithUserAgent(userAgent string) ClientOps {
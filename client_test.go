@@ -1,7 +1,10 @@
 package tonicpow
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"testing"
 	"time"
 
@@ -11,7 +14,9 @@ import (
 )
 
 // newTestClient will return a client for testing purposes
-func newTestClient() (ClientInterface, error) {
+//
+// Additional options are applied after the default test options
+func newTestClient(opts ...ClientOps) (ClientInterface, error) {
 	// Create a Resty Client
 	client := resty.New()
 
@@ -23,12 +28,12 @@ func newTestClient() (ClientInterface, error) {
 	headers["custom_header_1"] = append(headers["custom_header_1"], "value_1")
 
 	// Create a new client
-	newClient, err := NewClient(
+	newClient, err := NewClient(append([]ClientOps{
 		WithRequestTracing(),
 		WithAPIKey(testAPIKey),
 		WithEnvironment(EnvironmentDevelopment),
 		WithCustomHeaders(headers),
-	)
+	}, opts...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -83,6 +88,16 @@ func TestNewClient(t *testing.T) {
 		assert.Equal(t, 3, client.Options().retryCount)
 	})
 
+	t.Run("custom retry policy", func(t *testing.T) {
+		policy := DefaultRetryPolicy()
+		policy.MaxAttempts = 5
+		client, err := NewClient(WithAPIKey(testAPIKey), WithRetryPolicy(policy))
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+		assert.NotNil(t, client.Options().retryPolicy)
+		assert.Equal(t, 5, client.Options().retryPolicy.MaxAttempts)
+	})
+
 	t.Run("custom headers", func(t *testing.T) {
 		headers := make(map[string][]string)
 		headers["custom_header_1"] = append(headers["custom_header_1"], "value_1")
@@ -198,6 +213,53 @@ func TestClient_GetEnvironment(t *testing.T) {
 	})
 }
 
+// TestClient_RequestWithContext will test the method RequestWithContext()
+func TestClient_RequestWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s/details/%d", EnvironmentDevelopment.apiURL, modelGoal, testGoalID)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestGoal())
+		assert.NoError(t, err)
+
+		var response *StandardResponse
+		response, err = client.RequestWithContext(
+			context.Background(), http.MethodGet,
+			fmt.Sprintf("/%s/details/%d", modelGoal, testGoalID),
+			nil, http.StatusOK,
+		)
+		assert.NoError(t, err)
+		assert.NotNil(t, response)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestGoal())
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		var response *StandardResponse
+		response, err = client.RequestWithContext(
+			ctx, http.MethodGet,
+			fmt.Sprintf("/%s/details/%d", modelGoal, testGoalID),
+			nil, http.StatusOK,
+		)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, response)
+	})
+}
+
 // ExampleNewClient example using NewClient()
 //
 // See more examples in /examples/
@@ -232,6 +294,8 @@ func TestDefaultClientOptions(t *testing.T) {
 	assert.Equal(t, environmentLiveName, options.env.Name())
 	assert.Equal(t, false, options.requestTracing)
 	assert.Equal(t, liveAPIURL, options.env.URL())
+	assert.Equal(t, slog.LevelInfo, options.logLevel)
+	assert.Nil(t, options.logger)
 }
 
 // BenchmarkDefaultClientOptions benchmarks the method defaultClientOptions()
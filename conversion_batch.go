@@ -0,0 +1,81 @@
+package tonicpow
+
+import (
+	"context"
+	"sync"
+)
+
+// ConversionRequest is a conversion to create in a batch (see CreateConversions)
+type ConversionRequest struct {
+	Options []ConversionOps // Same options as CreateConversion()
+}
+
+// NewConversionRequest will return a new conversion request using the options
+func NewConversionRequest(opts ...ConversionOps) ConversionRequest {
+	return ConversionRequest{Options: opts}
+}
+
+// BatchOptions is the configuration for CreateConversions
+type BatchOptions struct {
+	Concurrency int                           // Number of conversions in flight (default: 4)
+	OnResult    func(result ConversionResult) // (optional) Called as each conversion completes (from the workers)
+}
+
+// ConversionResult is the result of a conversion request in a batch
+//
+// Err is the same error CreateConversion would return (IE: *Error, *ValidationError
+// or a context error), use errors.Is() with ErrRateLimited, ErrValidation etc.
+type ConversionResult struct {
+	Conversion *Conversion       // The new conversion (nil on error)
+	Err        error             // Error for this request (nil on success)
+	Index      int               // Index of the request in the batch
+	Response   *StandardResponse // The last response (nil if no request was made)
+}
+
+// CreateConversions will create a batch of conversions with bounded concurrency
+//
+// Every request gets a result (in the same order as the requests), a failed
+// conversion does not stop the batch. Requests use the client's rate limiter
+// & retry policy (see WithRateLimit and WithRetryPolicy), and requests that
+// have not started when the context is canceled fail with the context error.
+// Use WithIdempotencyKey so a batch can be replayed safely.
+func (c *Client) CreateConversions(ctx context.Context, requests []ConversionRequest,
+	options BatchOptions) []ConversionResult {
+
+	results := make([]ConversionResult, len(requests))
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	if concurrency > len(requests) {
+		concurrency = len(requests)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				result := ConversionResult{Index: index}
+				if result.Err = ctx.Err(); result.Err == nil {
+					result.Conversion, result.Response, result.Err = c.CreateConversionWithContext(
+						ctx, requests[index].Options...,
+					)
+				}
+				results[index] = result
+				if options.OnResult != nil {
+					options.OnResult(result)
+				}
+			}
+		}()
+	}
+
+	for index := range requests {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,125 @@
+package tonicpow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_CreateConversions will test the method CreateConversions()
+func TestClient_CreateConversions(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelConversion)
+
+	t.Run("per item results", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		var inFlight, maxInFlight int32
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodPost, endpoint, func(req *http.Request) (*http.Response, error) {
+			current := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				previous := atomic.LoadInt32(&maxInFlight)
+				if current <= previous || atomic.CompareAndSwapInt32(&maxInFlight, previous, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+
+			var payload map[string]string
+			if decodeErr := json.NewDecoder(req.Body).Decode(&payload); decodeErr != nil {
+				return nil, decodeErr
+			}
+			if payload[fieldGoalID] == "2" {
+				return httpmock.NewStringResponse(
+					http.StatusUnprocessableEntity, `{"code":422,"message":"goal is not active"}`,
+				), nil
+			}
+			return httpmock.NewJsonResponse(http.StatusCreated, newTestConversion())
+		})
+
+		requests := []ConversionRequest{
+			NewConversionRequest(WithGoalID(1), WithTncpwSession(testTncpwSession)),
+			NewConversionRequest(WithGoalID(2), WithTncpwSession(testTncpwSession)),
+			NewConversionRequest(WithTncpwSession(testTncpwSession)),
+		}
+		for i := 0; i < 5; i++ {
+			requests = append(requests, NewConversionRequest(WithGoalID(1), WithUserID(testUserID)))
+		}
+
+		var mu sync.Mutex
+		var reported int
+		results := client.CreateConversions(context.Background(), requests, BatchOptions{
+			Concurrency: 2,
+			OnResult: func(ConversionResult) {
+				mu.Lock()
+				reported++
+				mu.Unlock()
+			},
+		})
+
+		assert.Len(t, results, len(requests))
+		assert.Equal(t, len(requests), reported)
+		assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+		for i, result := range results {
+			assert.Equal(t, i, result.Index)
+		}
+
+		assert.NoError(t, results[0].Err)
+		if assert.NotNil(t, results[0].Conversion) {
+			assert.Equal(t, testConversionID, results[0].Conversion.ID)
+		}
+
+		assert.ErrorIs(t, results[1].Err, ErrValidation)
+		assert.Nil(t, results[1].Conversion)
+		if assert.NotNil(t, results[1].Response) {
+			assert.Equal(t, http.StatusUnprocessableEntity, results[1].Response.StatusCode)
+		}
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, results[2].Err, &validationErr)
+		assert.Nil(t, results[2].Response)
+
+		for _, result := range results[3:] {
+			assert.NoError(t, result.Err)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		err = mockResponseData(http.MethodPost, endpoint, http.StatusCreated, newTestConversion())
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results := client.CreateConversions(ctx, []ConversionRequest{
+			NewConversionRequest(WithGoalID(1), WithTncpwSession(testTncpwSession)),
+			NewConversionRequest(WithGoalID(1), WithTncpwSession(testTncpwSession)),
+		}, BatchOptions{})
+		assert.Len(t, results, 2)
+		for _, result := range results {
+			assert.ErrorIs(t, result.Err, context.Canceled)
+		}
+		assert.Equal(t, 0, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.Empty(t, client.CreateConversions(context.Background(), nil, BatchOptions{}))
+	})
+}
@@ -0,0 +1,353 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrOutboxEntryNotFound is returned when the outbox entry does not exist (or is not dead-lettered)
+var ErrOutboxEntryNotFound = errors.New("outbox entry not found")
+
+// OutboxEntryStatus is the status of a conversion in the outbox
+type OutboxEntryStatus string
+
+const (
+	// OutboxEntryPending is for a conversion waiting to be sent (or retried)
+	OutboxEntryPending OutboxEntryStatus = "pending"
+
+	// OutboxEntryDead is for a conversion that failed permanently (dead-lettered)
+	OutboxEntryDead OutboxEntryStatus = "dead"
+)
+
+// OutboxEntry is a conversion stored in the outbox
+//
+// The ID is the idempotency key used for every attempt, so a conversion is only
+// created once even if an attempt succeeded but the response was lost
+type OutboxEntry struct {
+	Attempts         int               `json:"attempts"`
+	CreatedAt        time.Time         `json:"created_at"`
+	CustomDimensions string            `json:"custom_dimensions,omitempty"`
+	DelayInMinutes   uint64            `json:"delay_in_minutes,omitempty"`
+	GoalID           uint64            `json:"goal_id,omitempty"`
+	GoalName         string            `json:"goal_name,omitempty"`
+	ID               string            `json:"id"`
+	LastError        string            `json:"last_error,omitempty"`
+	NextAttemptAt    time.Time         `json:"next_attempt_at"`
+	PurchaseAmount   Money             `json:"purchase_amount"`
+	ShortCode        string            `json:"short_code,omitempty"`
+	Status           OutboxEntryStatus `json:"status"`
+	TncpwSession     string            `json:"tncpw_session,omitempty"`
+	TwitterID        string            `json:"twitter_id,omitempty"`
+	UserID           uint64            `json:"user_id,omitempty"`
+}
+
+// conversionOps will return the conversion options for the entry
+func (e *OutboxEntry) conversionOps() []ConversionOps {
+	return []ConversionOps{
+		WithCustomDimensions(e.CustomDimensions),
+		WithDelay(e.DelayInMinutes),
+		WithGoalID(e.GoalID),
+		WithGoalName(e.GoalName),
+		WithIdempotencyKey(e.ID),
+		WithPurchaseAmountMoney(e.PurchaseAmount),
+		WithShortCode(e.ShortCode),
+		WithTncpwSession(e.TncpwSession),
+		WithTwitterID(e.TwitterID),
+		WithUserID(e.UserID),
+	}
+}
+
+// newOutboxEntry will return a new entry from the conversion options
+func newOutboxEntry(options *conversionOptions, now time.Time) *OutboxEntry {
+	return &OutboxEntry{
+		CreatedAt:        now,
+		CustomDimensions: options.customDimensions,
+		DelayInMinutes:   options.delayInMinutes,
+		GoalID:           options.goalID,
+		GoalName:         options.goalName,
+		ID:               options.idempotencyKey,
+		NextAttemptAt:    now,
+		PurchaseAmount:   options.purchaseAmount,
+		ShortCode:        options.shortCode,
+		Status:           OutboxEntryPending,
+		TncpwSession:     options.tncpwSession,
+		TwitterID:        options.twitterID,
+		UserID:           options.tonicPowUserID,
+	}
+}
+
+// OutboxOps allow functional options to be supplied
+// that overwrite default outbox options.
+type OutboxOps func(o *outboxOptions)
+
+// outboxOptions holds all the configuration for the outbox
+type outboxOptions struct {
+	maxAttempts  int                                              // Attempts before an entry is dead-lettered
+	maxBackoff   time.Duration                                    // Maximum delay between attempts
+	minBackoff   time.Duration                                    // Delay after the first failed attempt
+	now          func() time.Time                                 // Clock (for tests)
+	onDeadLetter func(entry *OutboxEntry, err error)              // Called when an entry is dead-lettered
+	onDelivered  func(entry *OutboxEntry, conversion *Conversion) // Called when an entry is delivered
+	onError      func(err error)                                  // Called when draining fails (IE: storage errors)
+	pollInterval time.Duration                                    // Delay between checks for due entries
+}
+
+// WithOutboxMaxAttempts will set the attempts before a conversion is dead-lettered
+// Default is 10.
+func WithOutboxMaxAttempts(attempts int) OutboxOps {
+	return func(o *outboxOptions) {
+		o.maxAttempts = attempts
+	}
+}
+
+// WithOutboxBackoff will set the delay after a failed attempt (doubling on each attempt, values <= 0 are ignored)
+// Default is 5 seconds up to 15 minutes.
+func WithOutboxBackoff(minBackoff, maxBackoff time.Duration) OutboxOps {
+	return func(o *outboxOptions) {
+		if minBackoff > 0 {
+			o.minBackoff = minBackoff
+		}
+		if maxBackoff > 0 {
+			o.maxBackoff = maxBackoff
+		}
+		if o.maxBackoff < o.minBackoff {
+			o.maxBackoff = o.minBackoff
+		}
+	}
+}
+
+// WithOutboxPollInterval will set the delay between checks for conversions that are due (values <= 0 are ignored)
+// Default is 1 second.
+func WithOutboxPollInterval(interval time.Duration) OutboxOps {
+	return func(o *outboxOptions) {
+		if interval > 0 {
+			o.pollInterval = interval
+		}
+	}
+}
+
+// WithOutboxDeadLetterHandler will set a function that is called when a conversion is dead-lettered
+func WithOutboxDeadLetterHandler(handler func(entry *OutboxEntry, err error)) OutboxOps {
+	return func(o *outboxOptions) {
+		o.onDeadLetter = handler
+	}
+}
+
+// WithOutboxDeliveredHandler will set a function that is called when a conversion is created
+func WithOutboxDeliveredHandler(handler func(entry *OutboxEntry, conversion *Conversion)) OutboxOps {
+	return func(o *outboxOptions) {
+		o.onDelivered = handler
+	}
+}
+
+// WithOutboxErrorHandler will set a function that is called when draining fails (IE: storage errors)
+func WithOutboxErrorHandler(handler func(err error)) OutboxOps {
+	return func(o *outboxOptions) {
+		o.onError = handler
+	}
+}
+
+// ConversionOutbox is a durable queue of conversions that are sent in the background
+//
+// Conversions are saved to the storage before they are sent, retried with backoff
+// when the API is unreachable (or rate limited) and dead-lettered when they fail
+// permanently (IE: validation errors) or run out of attempts
+type ConversionOutbox struct {
+	client  ConversionService
+	drainMu sync.Mutex
+	options *outboxOptions
+	storage OutboxStorage
+	wake    chan struct{}
+}
+
+// NewConversionOutbox will return a new outbox using the client & storage
+func NewConversionOutbox(client ConversionService, storage OutboxStorage, opts ...OutboxOps) *ConversionOutbox {
+	options := &outboxOptions{
+		maxAttempts:  defaultOutboxMaxAttempts,
+		maxBackoff:   defaultOutboxMaxBackoff,
+		minBackoff:   defaultOutboxMinBackoff,
+		now:          time.Now,
+		pollInterval: defaultOutboxPollInterval,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return &ConversionOutbox{
+		client:  client,
+		options: options,
+		storage: storage,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Enqueue will validate and save the conversion, it will be sent by Run() or Drain()
+//
+// An idempotency key is generated when the options do not have one (see WithIdempotencyKey),
+// enqueuing the same key again replaces the pending conversion
+func (o *ConversionOutbox) Enqueue(ctx context.Context, opts ...ConversionOps) (*OutboxEntry, error) {
+	options := new(conversionOptions)
+	for _, opt := range opts {
+		opt(options)
+	}
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	if len(options.idempotencyKey) == 0 {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate an idempotency key: %w", err)
+		}
+		options.idempotencyKey = key
+	}
+
+	entry := newOutboxEntry(options, o.options.now().UTC())
+	if err := o.storage.Save(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	// Wake up the drain loop
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return entry, nil
+}
+
+// Run will drain the outbox until the context is canceled
+func (o *ConversionOutbox) Run(ctx context.Context) error {
+	ticker := time.NewTicker(o.options.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := o.Drain(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if o.options.onError != nil {
+				o.options.onError(err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-o.wake:
+		}
+	}
+}
+
+// Drain will send all conversions that are due (oldest first)
+//
+// Failed attempts are rescheduled or dead-lettered, an error is only
+// returned if the storage fails or the context is canceled
+func (o *ConversionOutbox) Drain(ctx context.Context) error {
+	o.drainMu.Lock()
+	defer o.drainMu.Unlock()
+
+	entries, err := o.storage.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Status != OutboxEntryPending || entry.NextAttemptAt.After(o.options.now()) {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if err = o.send(ctx, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pending will return the conversions waiting to be sent
+func (o *ConversionOutbox) Pending(ctx context.Context) ([]*OutboxEntry, error) {
+	return o.entries(ctx, OutboxEntryPending)
+}
+
+// DeadLetters will return the conversions that failed permanently
+func (o *ConversionOutbox) DeadLetters(ctx context.Context) ([]*OutboxEntry, error) {
+	return o.entries(ctx, OutboxEntryDead)
+}
+
+// Requeue will move a dead-lettered conversion back to pending (with a new set of attempts)
+func (o *ConversionOutbox) Requeue(ctx context.Context, id string) error {
+	entries, err := o.entries(ctx, OutboxEntryDead)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.ID == id {
+			entry.Attempts = 0
+			entry.NextAttemptAt = o.options.now().UTC()
+			entry.Status = OutboxEntryPending
+			return o.storage.Save(ctx, entry)
+		}
+	}
+	return ErrOutboxEntryNotFound
+}
+
+// send will attempt to create the conversion and update the entry
+func (o *ConversionOutbox) send(ctx context.Context, entry *OutboxEntry) error {
+	conversion, _, err := o.client.CreateConversionWithContext(ctx, entry.conversionOps()...)
+	if err == nil {
+		if err = o.storage.Delete(ctx, entry.ID); err != nil {
+			return err
+		}
+		if o.options.onDelivered != nil {
+			o.options.onDelivered(entry, conversion)
+		}
+		return nil
+	}
+
+	// Canceled, leave the entry as it is
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	entry.Attempts++
+	entry.LastError = err.Error()
+	if isPermanentError(err) || entry.Attempts >= o.options.maxAttempts {
+		entry.Status = OutboxEntryDead
+		if saveErr := o.storage.Save(ctx, entry); saveErr != nil {
+			return saveErr
+		}
+		if o.options.onDeadLetter != nil {
+			o.options.onDeadLetter(entry, err)
+		}
+		return nil
+	}
+	entry.NextAttemptAt = o.options.now().UTC().Add(o.backoff(entry.Attempts))
+	return o.storage.Save(ctx, entry)
+}
+
+// backoff will return the delay after the given number of failed attempts
+func (o *ConversionOutbox) backoff(attempts int) time.Duration {
+	backoff := o.options.minBackoff
+	for i := 1; i < attempts && backoff < o.options.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > o.options.maxBackoff {
+		return o.options.maxBackoff
+	}
+	return backoff
+}
+
+// entries will return the entries with the status
+func (o *ConversionOutbox) entries(ctx context.Context, status OutboxEntryStatus) ([]*OutboxEntry, error) {
+	entries, err := o.storage.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*OutboxEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Status == status {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,265 @@
+package tonicpow
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// OutboxStorage stores the entries of a ConversionOutbox
+//
+// Implement this interface to keep the outbox in a database (IE: alongside orders)
+type OutboxStorage interface {
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]*OutboxEntry, error)
+	Save(ctx context.Context, entry *OutboxEntry) error
+}
+
+// MemoryOutboxStorage is an in-memory OutboxStorage (entries are lost on restart, useful for tests)
+type MemoryOutboxStorage struct {
+	entries map[string]*OutboxEntry
+	mu      sync.Mutex
+}
+
+// NewMemoryOutboxStorage will return a new in-memory OutboxStorage
+func NewMemoryOutboxStorage() *MemoryOutboxStorage {
+	return &MemoryOutboxStorage{entries: make(map[string]*OutboxEntry)}
+}
+
+// Delete will remove the entry
+func (m *MemoryOutboxStorage) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, id)
+	return nil
+}
+
+// List will return copies of all entries (oldest first)
+func (m *MemoryOutboxStorage) List(_ context.Context) ([]*OutboxEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return sortedOutboxEntries(m.entries), nil
+}
+
+// Save will add or replace the entry
+func (m *MemoryOutboxStorage) Save(_ context.Context, entry *OutboxEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	saved := *entry
+	m.entries[entry.ID] = &saved
+	return nil
+}
+
+// fileOutboxRecord is a line in the outbox log file
+type fileOutboxRecord struct {
+	Entry *OutboxEntry `json:"entry,omitempty"`
+	ID    string       `json:"id,omitempty"`
+	Op    string       `json:"op"`
+}
+
+// Outbox log operations
+const (
+	fileOutboxOpDelete = "delete"
+	fileOutboxOpSave   = "save"
+)
+
+// FileOutboxStorage is an OutboxStorage using an append-only log file (JSON lines)
+//
+// Every change is appended and synced to disk before returning. The log is
+// compacted when it is opened and when it grows past twice the live entries.
+type FileOutboxStorage struct {
+	entries map[string]*OutboxEntry
+	file    *os.File
+	mu      sync.Mutex
+	path    string
+	records int
+}
+
+// NewFileOutboxStorage will open (or create) the outbox log file
+//
+// A partially written or corrupt last record (IE: a crash while appending) is ignored
+func NewFileOutboxStorage(path string) (*FileOutboxStorage, error) {
+	storage := &FileOutboxStorage{
+		entries: make(map[string]*OutboxEntry),
+		path:    path,
+	}
+	if err := storage.load(); err != nil {
+		return nil, err
+	}
+	if err := storage.compact(); err != nil {
+		return nil, err
+	}
+	return storage, nil
+}
+
+// Close will close the log file
+func (f *FileOutboxStorage) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// Delete will remove the entry
+func (f *FileOutboxStorage) Delete(_ context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.entries[id]; !ok {
+		return nil
+	}
+	if err := f.append(fileOutboxRecord{ID: id, Op: fileOutboxOpDelete}); err != nil {
+		return err
+	}
+	delete(f.entries, id)
+	f.compactIfNeeded()
+	return nil
+}
+
+// List will return copies of all entries (oldest first)
+func (f *FileOutboxStorage) List(_ context.Context) ([]*OutboxEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return sortedOutboxEntries(f.entries), nil
+}
+
+// Save will add or replace the entry
+func (f *FileOutboxStorage) Save(_ context.Context, entry *OutboxEntry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	saved := *entry
+	if err := f.append(fileOutboxRecord{Entry: &saved, Op: fileOutboxOpSave}); err != nil {
+		return err
+	}
+	f.entries[saved.ID] = &saved
+	f.compactIfNeeded()
+	return nil
+}
+
+// load will replay the log file into memory
+func (f *FileOutboxStorage) load() error {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(bytes.NewReader(data))
+	for line := 1; ; line++ {
+		var raw []byte
+		raw, err = reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) && len(bytes.TrimSpace(raw)) > 0 {
+			// Partially written last line (no newline), ignore it
+			return nil
+		} else if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		var record fileOutboxRecord
+		if err = json.Unmarshal(raw, &record); err != nil {
+			if _, peekErr := reader.Peek(1); errors.Is(peekErr, io.EOF) {
+				// Corrupt last record (the write was not completed), ignore it
+				return nil
+			}
+			return fmt.Errorf("invalid outbox record on line %d: %w", line, err)
+		}
+		switch record.Op {
+		case fileOutboxOpSave:
+			if record.Entry != nil {
+				f.entries[record.Entry.ID] = record.Entry
+			}
+		case fileOutboxOpDelete:
+			delete(f.entries, record.ID)
+		}
+	}
+}
+
+// append will write the record to the log file and sync it to disk
+//
+// A failed write is truncated so the next record starts on a clean line
+func (f *FileOutboxStorage) append(record fileOutboxRecord) error {
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	var info os.FileInfo
+	if info, err = f.file.Stat(); err != nil {
+		return err
+	}
+	if _, err = f.file.Write(append(data, '\n')); err == nil {
+		err = f.file.Sync()
+	}
+	if err != nil {
+		if truncateErr := f.file.Truncate(info.Size()); truncateErr != nil {
+			return errors.Join(err, truncateErr)
+		}
+		return err
+	}
+	f.records++
+	return nil
+}
+
+// compactIfNeeded will compact the log file when it has more than twice the live entries
+//
+// The record is already on disk, so a failed compaction is ignored (it is retried on the next change)
+func (f *FileOutboxStorage) compactIfNeeded() {
+	if f.records <= 2*len(f.entries)+defaultOutboxCompactThreshold {
+		return
+	}
+	_ = f.compact()
+}
+
+// compact will rewrite the log file with only the live entries (replacing the file atomically)
+func (f *FileOutboxStorage) compact() (err error) {
+	var buf bytes.Buffer
+	for _, entry := range sortedOutboxEntries(f.entries) {
+		var data []byte
+		if data, err = json.Marshal(fileOutboxRecord{Entry: entry, Op: fileOutboxOpSave}); err != nil {
+			return
+		}
+		buf.Write(append(data, '\n'))
+	}
+	if err = writeFileAtomic(f.path, buf.Bytes()); err != nil {
+		return
+	}
+
+	// Re-open the compacted file for appending
+	if f.file != nil {
+		_ = f.file.Close()
+	}
+	if f.file, err = os.OpenFile(f.path, os.O_APPEND|os.O_WRONLY, 0o600); err != nil {
+		return
+	}
+	f.records = len(f.entries)
+	return
+}
+
+// sortedOutboxEntries will return copies of the entries (oldest first)
+func sortedOutboxEntries(entries map[string]*OutboxEntry) []*OutboxEntry {
+	list := make([]*OutboxEntry, 0, len(entries))
+	for _, entry := range entries {
+		e := *entry
+		list = append(list, &e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
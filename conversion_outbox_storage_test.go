@@ -0,0 +1,196 @@
+package tonicpow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestOutboxEntry will return a dummy entry for tests
+func newTestOutboxEntry(id string, created time.Time) *OutboxEntry {
+	return &OutboxEntry{
+		CreatedAt:    created,
+		GoalID:       testGoalID,
+		ID:           id,
+		Status:       OutboxEntryPending,
+		TncpwSession: testTncpwSession,
+	}
+}
+
+// TestFileOutboxStorage will test the FileOutboxStorage
+func TestFileOutboxStorage(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2021, 6, 4, 17, 0, 0, 0, time.UTC)
+
+	t.Run("entries survive a restart", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "outbox.log")
+		storage, err := NewFileOutboxStorage(path)
+		assert.NoError(t, err)
+
+		ctx := context.Background()
+		assert.NoError(t, storage.Save(ctx, newTestOutboxEntry("b", created.Add(time.Second))))
+		assert.NoError(t, storage.Save(ctx, newTestOutboxEntry("a", created)))
+		assert.NoError(t, storage.Save(ctx, newTestOutboxEntry("c", created)))
+
+		updated := newTestOutboxEntry("c", created)
+		updated.Attempts = 2
+		assert.NoError(t, storage.Save(ctx, updated))
+		assert.NoError(t, storage.Delete(ctx, "a"))
+		assert.NoError(t, storage.Delete(ctx, "missing"))
+		assert.NoError(t, storage.Close())
+		assert.NoError(t, storage.Close())
+
+		storage, err = NewFileOutboxStorage(path)
+		assert.NoError(t, err)
+		defer func() { _ = storage.Close() }()
+
+		var entries []*OutboxEntry
+		entries, err = storage.List(ctx)
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, "c", entries[0].ID)
+			assert.Equal(t, 2, entries[0].Attempts)
+			assert.Equal(t, "b", entries[1].ID)
+			assert.Equal(t, testTncpwSession, entries[1].TncpwSession)
+		}
+
+		// Opening compacts the log
+		var data []byte
+		data, err = os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(string(data), "\n"))
+	})
+
+	t.Run("partial last line is ignored", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "outbox.log")
+		storage, err := NewFileOutboxStorage(path)
+		assert.NoError(t, err)
+		assert.NoError(t, storage.Save(context.Background(), newTestOutboxEntry("a", created)))
+		assert.NoError(t, storage.Close())
+
+		var file *os.File
+		file, err = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+		assert.NoError(t, err)
+		_, err = file.WriteString(`{"op":"save","entry":{"id":"b"`)
+		assert.NoError(t, err)
+		assert.NoError(t, file.Close())
+
+		storage, err = NewFileOutboxStorage(path)
+		assert.NoError(t, err)
+		defer func() { _ = storage.Close() }()
+
+		var entries []*OutboxEntry
+		entries, err = storage.List(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("corrupt last record is ignored", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "outbox.log")
+		storage, err := NewFileOutboxStorage(path)
+		assert.NoError(t, err)
+		assert.NoError(t, storage.Save(context.Background(), newTestOutboxEntry("a", created)))
+		assert.NoError(t, storage.Close())
+
+		var file *os.File
+		file, err = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+		assert.NoError(t, err)
+		_, err = file.WriteString(`{"op":"save","entry":{"id":"b"` + "\n")
+		assert.NoError(t, err)
+		assert.NoError(t, file.Close())
+
+		storage, err = NewFileOutboxStorage(path)
+		assert.NoError(t, err)
+		defer func() { _ = storage.Close() }()
+		assert.NoError(t, storage.Save(context.Background(), newTestOutboxEntry("c", created.Add(time.Second))))
+
+		var entries []*OutboxEntry
+		entries, err = storage.List(context.Background())
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, "a", entries[0].ID)
+			assert.Equal(t, "c", entries[1].ID)
+		}
+	})
+
+	t.Run("corrupted log", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "outbox.log")
+		assert.NoError(t, os.WriteFile(path, []byte("not json\n{}\n"), 0o600))
+
+		storage, err := NewFileOutboxStorage(path)
+		assert.Error(t, err)
+		assert.Nil(t, storage)
+	})
+
+	t.Run("log is compacted as it grows", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "outbox.log")
+		storage, err := NewFileOutboxStorage(path)
+		assert.NoError(t, err)
+		defer func() { _ = storage.Close() }()
+
+		entry := newTestOutboxEntry("a", created)
+		for i := 0; i < 3*defaultOutboxCompactThreshold; i++ {
+			entry.Attempts = i
+			assert.NoError(t, storage.Save(context.Background(), entry))
+		}
+
+		var data []byte
+		data, err = os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, strings.Count(string(data), "\n"), defaultOutboxCompactThreshold+2)
+	})
+
+	t.Run("failed compaction does not fail the save", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "outbox")
+		assert.NoError(t, os.Mkdir(dir, 0o700))
+		storage, err := NewFileOutboxStorage(filepath.Join(dir, "outbox.log"))
+		assert.NoError(t, err)
+		defer func() { _ = storage.Close() }()
+
+		// Removing the directory makes the atomic rewrite fail (the open log file can still be appended)
+		if err = os.RemoveAll(dir); err != nil {
+			t.Skipf("cannot remove an open log file: %s", err)
+		}
+		entry := newTestOutboxEntry("a", created)
+		for i := 0; i < 3*defaultOutboxCompactThreshold; i++ {
+			entry.Attempts = i
+			assert.NoError(t, storage.Save(context.Background(), entry))
+		}
+		assert.NoError(t, storage.Delete(context.Background(), entry.ID))
+	})
+
+	t.Run("closed storage", func(t *testing.T) {
+		storage, err := NewFileOutboxStorage(filepath.Join(t.TempDir(), "outbox.log"))
+		assert.NoError(t, err)
+		assert.NoError(t, storage.Close())
+		assert.ErrorIs(t, storage.Save(context.Background(), newTestOutboxEntry("a", created)), os.ErrClosed)
+	})
+}
+
+// TestMemoryOutboxStorage will test the MemoryOutboxStorage
+func TestMemoryOutboxStorage(t *testing.T) {
+	t.Parallel()
+
+	storage := NewMemoryOutboxStorage()
+	entry := newTestOutboxEntry("a", time.Now())
+	assert.NoError(t, storage.Save(context.Background(), entry))
+
+	// Entries are copied
+	entry.Attempts = 5
+	entries, err := storage.List(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, 0, entries[0].Attempts)
+	}
+
+	assert.NoError(t, storage.Delete(context.Background(), "a"))
+	entries, err = storage.List(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
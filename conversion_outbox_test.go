@@ -0,0 +1,278 @@
+package tonicpow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// testOutboxClient is a ConversionService returning scripted results
+type testOutboxClient struct {
+	ConversionService
+	keys    []string
+	mu      sync.Mutex
+	results []error
+}
+
+// CreateConversionWithContext will return the next scripted result (success when out of results)
+func (c *testOutboxClient) CreateConversionWithContext(_ context.Context,
+	opts ...ConversionOps) (*Conversion, *StandardResponse, error) {
+	options := new(conversionOptions)
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys = append(c.keys, options.idempotencyKey)
+	if len(c.results) > 0 {
+		err := c.results[0]
+		c.results = c.results[1:]
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return &Conversion{GoalID: options.goalID, ID: testConversionID}, nil, nil
+}
+
+// newTestOutbox will return an outbox with a fake clock
+func newTestOutbox(client ConversionService, opts ...OutboxOps) (*ConversionOutbox, *time.Time) {
+	now := time.Date(2021, 6, 4, 17, 0, 0, 0, time.UTC)
+	opts = append([]OutboxOps{func(o *outboxOptions) {
+		o.now = func() time.Time { return now }
+	}}, opts...)
+	return NewConversionOutbox(client, NewMemoryOutboxStorage(), opts...), &now
+}
+
+// TestConversionOutbox will test the ConversionOutbox
+func TestConversionOutbox(t *testing.T) {
+	t.Parallel()
+
+	t.Run("enqueue and drain", func(t *testing.T) {
+		client := &testOutboxClient{}
+		var delivered []*Conversion
+		outbox, _ := newTestOutbox(client, WithOutboxDeliveredHandler(func(_ *OutboxEntry, conversion *Conversion) {
+			delivered = append(delivered, conversion)
+		}))
+
+		entry, err := outbox.Enqueue(context.Background(), WithGoalID(testGoalID), WithTncpwSession(testTncpwSession))
+		assert.NoError(t, err)
+		assert.Len(t, entry.ID, 32)
+		assert.Equal(t, OutboxEntryPending, entry.Status)
+
+		var pending []*OutboxEntry
+		pending, err = outbox.Pending(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, pending, 1)
+
+		assert.NoError(t, outbox.Drain(context.Background()))
+		assert.Equal(t, []string{entry.ID}, client.keys)
+		assert.Len(t, delivered, 1)
+
+		pending, err = outbox.Pending(context.Background())
+		assert.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("invalid conversion is not enqueued", func(t *testing.T) {
+		outbox, _ := newTestOutbox(&testOutboxClient{})
+		entry, err := outbox.Enqueue(context.Background(), WithGoalID(testGoalID))
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Nil(t, entry)
+	})
+
+	t.Run("exact purchase amount", func(t *testing.T) {
+		outbox, _ := newTestOutbox(&testOutboxClient{})
+		entry, err := outbox.Enqueue(context.Background(), WithGoalID(testGoalID), WithUserID(testUserID),
+			WithPurchaseAmountMoney(mustParseMoney(t, "12345678901.23456789", CurrencyUSD)))
+		assert.NoError(t, err)
+
+		var data []byte
+		data, err = json.Marshal(entry)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"purchase_amount":12345678901.23456789`)
+
+		decoded := new(OutboxEntry)
+		assert.NoError(t, json.Unmarshal(data, decoded))
+		options := new(conversionOptions)
+		for _, opt := range decoded.conversionOps() {
+			opt(options)
+		}
+		assert.Equal(t, "12345678901.23456789", options.payload()[fieldAmount])
+	})
+
+	t.Run("same idempotency key replaces the entry", func(t *testing.T) {
+		outbox, _ := newTestOutbox(&testOutboxClient{})
+		for i := 0; i < 2; i++ {
+			_, err := outbox.Enqueue(
+				context.Background(), WithGoalID(testGoalID), WithUserID(testUserID), WithIdempotencyKey("order-1"),
+			)
+			assert.NoError(t, err)
+		}
+		pending, err := outbox.Pending(context.Background())
+		assert.NoError(t, err)
+		if assert.Len(t, pending, 1) {
+			assert.Equal(t, "order-1", pending[0].ID)
+		}
+	})
+
+	t.Run("retries with backoff and the same key", func(t *testing.T) {
+		unavailable := &Error{Code: 503, StatusCode: http.StatusServiceUnavailable}
+		client := &testOutboxClient{results: []error{unavailable, unavailable}}
+		outbox, now := newTestOutbox(client, WithOutboxBackoff(time.Second, time.Minute))
+
+		entry, err := outbox.Enqueue(context.Background(), WithGoalName(testGoalName), WithShortCode("abc"))
+		assert.NoError(t, err)
+
+		assert.NoError(t, outbox.Drain(context.Background()))
+		pending, _ := outbox.Pending(context.Background())
+		if assert.Len(t, pending, 1) {
+			assert.Equal(t, 1, pending[0].Attempts)
+			assert.Equal(t, now.Add(time.Second), pending[0].NextAttemptAt)
+			assert.NotEmpty(t, pending[0].LastError)
+		}
+
+		// Not due yet
+		assert.NoError(t, outbox.Drain(context.Background()))
+		assert.Len(t, client.keys, 1)
+
+		*now = now.Add(time.Second)
+		assert.NoError(t, outbox.Drain(context.Background()))
+		pending, _ = outbox.Pending(context.Background())
+		if assert.Len(t, pending, 1) {
+			assert.Equal(t, 2, pending[0].Attempts)
+			assert.Equal(t, now.Add(2*time.Second), pending[0].NextAttemptAt)
+		}
+
+		*now = now.Add(time.Minute)
+		assert.NoError(t, outbox.Drain(context.Background()))
+		pending, _ = outbox.Pending(context.Background())
+		assert.Empty(t, pending)
+		assert.Equal(t, []string{entry.ID, entry.ID, entry.ID}, client.keys)
+	})
+
+	t.Run("dead letters", func(t *testing.T) {
+		client := &testOutboxClient{results: []error{
+			&Error{Code: 422, Message: "goal not found", StatusCode: http.StatusUnprocessableEntity},
+			errors.New("connection refused"),
+		}}
+		var dead []string
+		outbox, _ := newTestOutbox(
+			client,
+			WithOutboxMaxAttempts(1),
+			WithOutboxDeadLetterHandler(func(entry *OutboxEntry, err error) {
+				dead = append(dead, entry.ID+": "+err.Error())
+			}),
+		)
+
+		_, err := outbox.Enqueue(context.Background(), WithGoalID(1), WithTwitterID("twitter"), WithIdempotencyKey("a"))
+		assert.NoError(t, err)
+		_, err = outbox.Enqueue(context.Background(), WithGoalID(2), WithTwitterID("twitter"), WithIdempotencyKey("b"))
+		assert.NoError(t, err)
+
+		assert.NoError(t, outbox.Drain(context.Background()))
+		assert.Equal(t, []string{"a: goal not found", "b: connection refused"}, dead)
+
+		var letters []*OutboxEntry
+		letters, err = outbox.DeadLetters(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, letters, 2)
+
+		// Requeue and deliver
+		assert.NoError(t, outbox.Requeue(context.Background(), "b"))
+		assert.ErrorIs(t, outbox.Requeue(context.Background(), "missing"), ErrOutboxEntryNotFound)
+		assert.NoError(t, outbox.Drain(context.Background()))
+
+		letters, err = outbox.DeadLetters(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, letters, 1)
+	})
+
+	t.Run("run drains in the background", func(t *testing.T) {
+		client := &testOutboxClient{}
+		delivered := make(chan *OutboxEntry, 1)
+		outbox := NewConversionOutbox(
+			client, NewMemoryOutboxStorage(),
+			WithOutboxPollInterval(time.Hour),
+			WithOutboxDeliveredHandler(func(entry *OutboxEntry, _ *Conversion) {
+				delivered <- entry
+			}),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- outbox.Run(ctx) }()
+
+		entry, err := outbox.Enqueue(context.Background(), WithGoalID(testGoalID), WithUserID(testUserID))
+		assert.NoError(t, err)
+
+		select {
+		case got := <-delivered:
+			assert.Equal(t, entry.ID, got.ID)
+		case <-time.After(5 * time.Second):
+			t.Fatal("conversion was not delivered")
+		}
+
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		outbox, _ := newTestOutbox(nil, WithOutboxBackoff(time.Second, 5*time.Second))
+		assert.Equal(t, time.Second, outbox.backoff(1))
+		assert.Equal(t, 2*time.Second, outbox.backoff(2))
+		assert.Equal(t, 4*time.Second, outbox.backoff(3))
+		assert.Equal(t, 5*time.Second, outbox.backoff(4))
+		assert.Equal(t, 5*time.Second, outbox.backoff(100))
+	})
+
+	t.Run("invalid backoff is ignored", func(t *testing.T) {
+		outbox, _ := newTestOutbox(nil, WithOutboxBackoff(0, -time.Second))
+		assert.Equal(t, defaultOutboxMinBackoff, outbox.options.minBackoff)
+		assert.Equal(t, defaultOutboxMaxBackoff, outbox.options.maxBackoff)
+
+		outbox, _ = newTestOutbox(nil, WithOutboxBackoff(time.Hour, 0))
+		assert.Equal(t, time.Hour, outbox.backoff(1))
+		assert.Equal(t, time.Hour, outbox.backoff(100))
+	})
+
+	t.Run("invalid poll interval is ignored", func(t *testing.T) {
+		outbox, _ := newTestOutbox(nil, WithOutboxPollInterval(0), WithOutboxPollInterval(-time.Second))
+		assert.Equal(t, defaultOutboxPollInterval, outbox.options.pollInterval)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, outbox.Run(ctx), context.Canceled)
+	})
+}
+
+// TestConversionOutbox_Client will test the ConversionOutbox using the client
+func TestConversionOutbox_Client(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	client, err := newTestClient()
+	assert.NoError(t, err)
+
+	var keys []string
+	endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelConversion)
+	httpmock.Reset()
+	httpmock.RegisterResponder(http.MethodPost, endpoint, func(req *http.Request) (*http.Response, error) {
+		keys = append(keys, req.Header.Get(headerIdempotencyKey))
+		return httpmock.NewJsonResponse(http.StatusCreated, newTestConversion())
+	})
+
+	outbox := NewConversionOutbox(client, NewMemoryOutboxStorage())
+	var entry *OutboxEntry
+	entry, err = outbox.Enqueue(context.Background(), WithGoalID(testGoalID), WithTncpwSession(testTncpwSession))
+	assert.NoError(t, err)
+	assert.NoError(t, outbox.Drain(context.Background()))
+	assert.Equal(t, []string{entry.ID}, keys)
+}
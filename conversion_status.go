@@ -0,0 +1,224 @@
+package tonicpow
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WaitOptions is the configuration for WaitForConversion
+type WaitOptions struct {
+	Interval    time.Duration                // Delay before the first poll, doubling after each poll (default: 2 seconds)
+	MaxInterval time.Duration                // Maximum delay between polls (default: 30 seconds)
+	OnStatus    func(conversion *Conversion) // (optional) Called after each successful poll
+}
+
+// withDefaults will return the options with the defaults applied
+func (o WaitOptions) withDefaults() WaitOptions {
+	if o.Interval <= 0 {
+		o.Interval = defaultWaitInterval
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = defaultWaitMaxInterval
+	}
+	if o.MaxInterval < o.Interval {
+		o.MaxInterval = o.Interval
+	}
+	return o
+}
+
+// WaitForConversion will poll the conversion until it has a terminal status (paid, failed or cancelled)
+//
+// Polling backs off (see WaitOptions) and stops when the context is done. Temporary
+// errors (IE: rate limits, server errors) are retried, permanent errors (IE: not found)
+// are returned. Use a context deadline to limit how long to wait, the last polled
+// conversion (if any) is returned with the context error.
+func (c *Client) WaitForConversion(ctx context.Context, conversionID uint64,
+	options WaitOptions) (conversion *Conversion, err error) {
+
+	options = options.withDefaults()
+	delay := options.Interval
+	for {
+		var polled *Conversion
+		if polled, _, err = c.GetConversionWithContext(ctx, conversionID); err == nil {
+			conversion = polled
+			if options.OnStatus != nil {
+				options.OnStatus(conversion)
+			}
+			if conversion.Status.IsTerminal() {
+				return
+			}
+		} else if isPermanentError(err) {
+			return
+		} else if ctx.Err() != nil {
+			err = ctx.Err()
+			return
+		}
+
+		if err = sleepContext(ctx, delay); err != nil {
+			return
+		}
+		if delay *= 2; delay > options.MaxInterval {
+			delay = options.MaxInterval
+		}
+	}
+}
+
+// ConversionStatusEvent is a change in the status of a conversion
+type ConversionStatusEvent struct {
+	Conversion *Conversion      // The conversion (after the change)
+	Previous   ConversionStatus // Previous status (empty on the first poll)
+}
+
+// PollerOps allow functional options to be supplied
+// that overwrite default poller options.
+type PollerOps func(o *pollerOptions)
+
+// pollerOptions holds all the configuration for the poller
+type pollerOptions struct {
+	errorHandler func(conversionID uint64, err error) // Called when polling a conversion fails
+	interval     time.Duration                        // Delay between polls
+}
+
+// WithConversionPollInterval will set the delay between polls (values <= 0 are ignored)
+// Default is 10 seconds.
+func WithConversionPollInterval(interval time.Duration) PollerOps {
+	return func(o *pollerOptions) {
+		if interval > 0 {
+			o.interval = interval
+		}
+	}
+}
+
+// WithConversionPollErrorHandler will set a function that is called when polling a conversion fails
+//
+// Conversions with permanent errors (IE: not found) are removed from the poller
+func WithConversionPollErrorHandler(handler func(conversionID uint64, err error)) PollerOps {
+	return func(o *pollerOptions) {
+		o.errorHandler = handler
+	}
+}
+
+// ConversionPoller polls many conversions and emits an event each time a status changes
+//
+// Conversions are removed once they reach a terminal status (paid, failed or cancelled)
+type ConversionPoller struct {
+	client   ConversionService
+	mu       sync.Mutex
+	options  *pollerOptions
+	statuses map[uint64]ConversionStatus
+}
+
+// NewConversionPoller will return a new poller using the client
+func NewConversionPoller(client ConversionService, opts ...PollerOps) *ConversionPoller {
+	options := &pollerOptions{interval: defaultPollInterval}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return &ConversionPoller{
+		client:   client,
+		options:  options,
+		statuses: make(map[uint64]ConversionStatus),
+	}
+}
+
+// Add will start polling the conversions (it is safe to add while watching)
+func (p *ConversionPoller) Add(conversionIDs ...uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, id := range conversionIDs {
+		if _, ok := p.statuses[id]; !ok && id > 0 {
+			p.statuses[id] = ""
+		}
+	}
+}
+
+// Remove will stop polling the conversions
+func (p *ConversionPoller) Remove(conversionIDs ...uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, id := range conversionIDs {
+		delete(p.statuses, id)
+	}
+}
+
+// Len will return the number of conversions being polled
+func (p *ConversionPoller) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.statuses)
+}
+
+// Watch will poll the conversions until the context is canceled, sending the events on the returned channel
+//
+// The channel is closed once the context is canceled
+func (p *ConversionPoller) Watch(ctx context.Context) <-chan ConversionStatusEvent {
+	events := make(chan ConversionStatusEvent)
+	go func() {
+		defer close(events)
+		for {
+			for _, event := range p.Poll(ctx) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if sleepContext(ctx, p.options.interval) != nil {
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// Poll will poll each conversion once and return the status changes (in conversion id order)
+func (p *ConversionPoller) Poll(ctx context.Context) (events []ConversionStatusEvent) {
+	for _, id := range p.ids() {
+		if ctx.Err() != nil {
+			return
+		}
+		conversion, _, err := p.client.GetConversionWithContext(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if p.options.errorHandler != nil {
+				p.options.errorHandler(id, err)
+			}
+			if isPermanentError(err) {
+				p.Remove(id)
+			}
+			continue
+		}
+
+		p.mu.Lock()
+		previous, ok := p.statuses[id]
+		if ok {
+			if conversion.Status.IsTerminal() {
+				delete(p.statuses, id)
+			} else {
+				p.statuses[id] = conversion.Status
+			}
+		}
+		p.mu.Unlock()
+
+		if ok && previous != conversion.Status {
+			events = append(events, ConversionStatusEvent{Conversion: conversion, Previous: previous})
+		}
+	}
+	return
+}
+
+// ids will return the conversion ids being polled (sorted)
+func (p *ConversionPoller) ids() []uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ids := make([]uint64, 0, len(p.statuses))
+	for id := range p.statuses {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,253 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// testStatusClient is a ConversionService that returns statuses in order
+type testStatusClient struct {
+	ConversionService
+	errs     map[uint64]error
+	mu       sync.Mutex
+	statuses map[uint64][]ConversionStatus
+}
+
+// GetConversionWithContext will return the next status for the conversion
+func (c *testStatusClient) GetConversionWithContext(_ context.Context,
+	conversionID uint64) (*Conversion, *StandardResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.errs[conversionID]; err != nil {
+		return nil, nil, err
+	}
+	statuses := c.statuses[conversionID]
+	status := statuses[0]
+	if len(statuses) > 1 {
+		c.statuses[conversionID] = statuses[1:]
+	}
+	return &Conversion{ID: conversionID, Status: status}, nil, nil
+}
+
+// TestConversionStatus_IsTerminal will test the method IsTerminal()
+func TestConversionStatus_IsTerminal(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, ConversionStatusPending.IsTerminal())
+	assert.False(t, ConversionStatusProcessing.IsTerminal())
+	assert.True(t, ConversionStatusPaid.IsTerminal())
+	assert.True(t, ConversionStatusFailed.IsTerminal())
+	assert.True(t, ConversionStatusCancelled.IsTerminal())
+	assert.False(t, ConversionStatus("").IsTerminal())
+}
+
+// TestClient_WaitForConversion will test the method WaitForConversion()
+func TestClient_WaitForConversion(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s/details/%d", EnvironmentDevelopment.apiURL, modelConversion, testConversionID)
+	options := WaitOptions{Interval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+
+	t.Run("wait until paid", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		conversion := newTestConversion()
+		conversion.Status = ConversionStatusPaid
+		conversion.TxID = "b4d8f9e1c0a7"
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, conversion)
+		assert.NoError(t, err)
+
+		var polled *Conversion
+		opts := options
+		opts.OnStatus = func(c *Conversion) { polled = c }
+
+		var result *Conversion
+		result, err = client.WaitForConversion(context.Background(), testConversionID, opts)
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Equal(t, ConversionStatusPaid, result.Status)
+		assert.Equal(t, "b4d8f9e1c0a7", result.TxID)
+		assert.Equal(t, result, polled)
+	})
+
+	t.Run("permanent error", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		apiError := &Error{
+			Code:        404,
+			Data:        "not found",
+			IPAddress:   "127.0.0.1",
+			Message:     "conversion not found",
+			Method:      http.MethodGet,
+			RequestGUID: "7f3d97a8fd67ff57861904df6118dcc8",
+			StatusCode:  http.StatusNotFound,
+			URL:         endpoint,
+		}
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusNotFound, apiError)
+		assert.NoError(t, err)
+
+		var result *Conversion
+		result, err = client.WaitForConversion(context.Background(), testConversionID, options)
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("context deadline while pending", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		conversion := newTestConversion()
+		conversion.Status = ConversionStatusPending
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, conversion)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		var result *Conversion
+		result, err = client.WaitForConversion(ctx, testConversionID, options)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NotNil(t, result)
+		assert.Equal(t, ConversionStatusPending, result.Status)
+	})
+
+	t.Run("context deadline during a poll returns the last polled conversion", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		conversion := newTestConversion()
+		conversion.Status = ConversionStatusProcessing
+		polls := 0
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodGet, endpoint, func(req *http.Request) (*http.Response, error) {
+			if polls++; polls == 1 {
+				return httpmock.NewJsonResponse(http.StatusOK, conversion)
+			}
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		var result *Conversion
+		result, err = client.WaitForConversion(ctx, testConversionID, options)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.GreaterOrEqual(t, polls, 2)
+		if assert.NotNil(t, result) {
+			assert.Equal(t, ConversionStatusProcessing, result.Status)
+			assert.Equal(t, testConversionID, result.ID)
+		}
+	})
+}
+
+// TestWaitOptions_withDefaults will test the method withDefaults()
+func TestWaitOptions_withDefaults(t *testing.T) {
+	t.Parallel()
+
+	options := WaitOptions{}.withDefaults()
+	assert.Equal(t, defaultWaitInterval, options.Interval)
+	assert.Equal(t, defaultWaitMaxInterval, options.MaxInterval)
+
+	options = WaitOptions{Interval: time.Minute, MaxInterval: time.Second}.withDefaults()
+	assert.Equal(t, time.Minute, options.MaxInterval)
+}
+
+// TestConversionPoller will test the ConversionPoller
+func TestConversionPoller(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports transitions until terminal", func(t *testing.T) {
+		client := &testStatusClient{statuses: map[uint64][]ConversionStatus{
+			1: {ConversionStatusPending, ConversionStatusPending, ConversionStatusProcessing, ConversionStatusPaid},
+			2: {ConversionStatusFailed},
+		}}
+		poller := NewConversionPoller(client)
+		poller.Add(1, 2, 0)
+		assert.Equal(t, 2, poller.Len())
+
+		events := poller.Poll(context.Background())
+		assert.Len(t, events, 2)
+		assert.Equal(t, uint64(1), events[0].Conversion.ID)
+		assert.Equal(t, ConversionStatus(""), events[0].Previous)
+		assert.Equal(t, ConversionStatusPending, events[0].Conversion.Status)
+		assert.Equal(t, ConversionStatusFailed, events[1].Conversion.Status)
+		assert.Equal(t, 1, poller.Len())
+
+		events = poller.Poll(context.Background())
+		assert.Empty(t, events)
+
+		events = poller.Poll(context.Background())
+		assert.Len(t, events, 1)
+		assert.Equal(t, ConversionStatusPending, events[0].Previous)
+		assert.Equal(t, ConversionStatusProcessing, events[0].Conversion.Status)
+
+		events = poller.Poll(context.Background())
+		assert.Len(t, events, 1)
+		assert.Equal(t, ConversionStatusPaid, events[0].Conversion.Status)
+		assert.Equal(t, 0, poller.Len())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		client := &testStatusClient{
+			errs: map[uint64]error{
+				1: &Error{StatusCode: http.StatusNotFound},
+				2: errors.New("connection reset"),
+			},
+		}
+		var failed []uint64
+		poller := NewConversionPoller(client, WithConversionPollErrorHandler(func(id uint64, err error) {
+			assert.Error(t, err)
+			failed = append(failed, id)
+		}))
+		poller.Add(1, 2)
+
+		events := poller.Poll(context.Background())
+		assert.Empty(t, events)
+		assert.Equal(t, []uint64{1, 2}, failed)
+		assert.Equal(t, 1, poller.Len())
+
+		poller.Remove(2)
+		assert.Equal(t, 0, poller.Len())
+	})
+
+	t.Run("non-positive interval is ignored", func(t *testing.T) {
+		poller := NewConversionPoller(&testStatusClient{}, WithConversionPollInterval(0), WithConversionPollInterval(-time.Second))
+		assert.Equal(t, defaultPollInterval, poller.options.interval)
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		client := &testStatusClient{statuses: map[uint64][]ConversionStatus{
+			testConversionID: {ConversionStatusProcessing, ConversionStatusCancelled},
+		}}
+		poller := NewConversionPoller(client, WithConversionPollInterval(time.Millisecond))
+		poller.Add(testConversionID)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var statuses []ConversionStatus
+		for event := range poller.Watch(ctx) {
+			statuses = append(statuses, event.Conversion.Status)
+			if event.Conversion.Status.IsTerminal() {
+				cancel()
+			}
+		}
+		assert.Equal(t, []ConversionStatus{ConversionStatusProcessing, ConversionStatusCancelled}, statuses)
+	})
+}
@@ -0,0 +1,225 @@
+package tonicpow
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TrackedConversion is a delayed conversion that can be canceled until the deadline
+type TrackedConversion struct {
+	CancelDeadline time.Time   // Last moment CancelConversion can succeed (payout_after minus one minute)
+	Conversion     *Conversion // The conversion (as created)
+	PayoutAfter    time.Time   // When the conversion is paid out
+}
+
+// TrackerOps allow functional options to be supplied
+// that overwrite default tracker options.
+type TrackerOps func(o *trackerOptions)
+
+// trackerOptions holds all the configuration for the tracker
+type trackerOptions struct {
+	now       func() time.Time                // Clock
+	onClosing func(tracked TrackedConversion) // Called shortly before the cancel window closes
+	onExpired func(tracked TrackedConversion) // Called when the cancel window has closed
+	warning   time.Duration                   // How long before the deadline onClosing is called
+}
+
+// WithClosingHandler will set a function that is called shortly before the cancel window closes
+// Default warning is 5 minutes before the cancel deadline.
+func WithClosingHandler(warning time.Duration, handler func(tracked TrackedConversion)) TrackerOps {
+	return func(o *trackerOptions) {
+		o.onClosing = handler
+		o.warning = warning
+	}
+}
+
+// WithExpiredHandler will set a function that is called when the cancel window has closed
+// (the conversion is no longer tracked)
+func WithExpiredHandler(handler func(tracked TrackedConversion)) TrackerOps {
+	return func(o *trackerOptions) {
+		o.onExpired = handler
+	}
+}
+
+// trackedEntry is a tracked conversion and its timer
+type trackedEntry struct {
+	timer   *time.Timer
+	tracked TrackedConversion
+}
+
+// ConversionTracker tracks delayed conversions while they can still be canceled
+//
+// CancelConversion only succeeds while more than one minute remains before the payout,
+// the tracker computes that deadline from PayoutAfter and calls the closing handler
+// shortly before it, so refund flows can cancel in time
+type ConversionTracker struct {
+	client  ConversionService
+	entries map[uint64]*trackedEntry
+	mu      sync.Mutex
+	options *trackerOptions
+}
+
+// NewConversionTracker will return a new tracker using the client
+func NewConversionTracker(client ConversionService, opts ...TrackerOps) *ConversionTracker {
+	options := &trackerOptions{
+		now:     time.Now,
+		warning: defaultTrackerWarning,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return &ConversionTracker{
+		client:  client,
+		entries: make(map[uint64]*trackedEntry),
+		options: options,
+	}
+}
+
+// CreateConversion will create the conversion (see CreateConversion) and track it if it has a delay
+func (t *ConversionTracker) CreateConversion(ctx context.Context, opts ...ConversionOps) (conversion *Conversion,
+	response *StandardResponse, err error) {
+	if conversion, response, err = t.client.CreateConversionWithContext(ctx, opts...); err != nil {
+		return
+	}
+
+	// Track conversions that have a delay
+	options := new(conversionOptions)
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.delayInMinutes > 0 {
+		t.Track(conversion, time.Duration(options.delayInMinutes)*time.Minute)
+	}
+	return
+}
+
+// Track will start tracking the conversion
+//
+// The payout time is taken from PayoutAfter, or is the delay from now when
+// PayoutAfter is not set. Conversions that can no longer be canceled are not tracked.
+func (t *ConversionTracker) Track(conversion *Conversion, delay time.Duration) (tracked TrackedConversion, ok bool) {
+	if conversion == nil || conversion.ID == 0 {
+		return
+	}
+
+	now := t.options.now()
+	payoutAfter := parseAPITime(conversion.PayoutAfter)
+	if payoutAfter.IsZero() {
+		payoutAfter = now.Add(delay)
+	}
+	tracked = TrackedConversion{
+		CancelDeadline: payoutAfter.Add(-conversionCancelMargin),
+		Conversion:     conversion,
+		PayoutAfter:    payoutAfter,
+	}
+	if !now.Before(tracked.CancelDeadline) {
+		return tracked, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if existing, exists := t.entries[conversion.ID]; exists {
+		existing.timer.Stop()
+	}
+	entry := &trackedEntry{tracked: tracked}
+	t.entries[conversion.ID] = entry
+	t.schedule(entry, now)
+	return tracked, true
+}
+
+// Tracked will return the tracked conversion (if it can still be canceled)
+func (t *ConversionTracker) Tracked(conversionID uint64) (tracked TrackedConversion, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var entry *trackedEntry
+	if entry, ok = t.entries[conversionID]; !ok {
+		return
+	}
+	if !t.options.now().Before(entry.tracked.CancelDeadline) {
+		return entry.tracked, false
+	}
+	return entry.tracked, true
+}
+
+// Cancellable will return true if the conversion is tracked and can still be canceled
+func (t *ConversionTracker) Cancellable(conversionID uint64) bool {
+	_, ok := t.Tracked(conversionID)
+	return ok
+}
+
+// CancelIfPending will cancel the conversion if it can still be canceled
+//
+// Returns canceled = false (and no error) if the conversion is not tracked or
+// the cancel window has closed, the conversion is no longer tracked once canceled
+func (t *ConversionTracker) CancelIfPending(ctx context.Context, conversionID uint64,
+	reason string) (canceled bool, conversion *Conversion, err error) {
+	if !t.Cancellable(conversionID) {
+		return
+	}
+	if conversion, _, err = t.client.CancelConversionWithContext(ctx, conversionID, reason); err != nil {
+		return
+	}
+	t.Untrack(conversionID)
+	return true, conversion, nil
+}
+
+// Untrack will stop tracking the conversion (no handlers are called)
+func (t *ConversionTracker) Untrack(conversionID uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if entry, ok := t.entries[conversionID]; ok {
+		entry.timer.Stop()
+		delete(t.entries, conversionID)
+	}
+}
+
+// Close will stop tracking all conversions (no handlers are called)
+func (t *ConversionTracker) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, entry := range t.entries {
+		entry.timer.Stop()
+		delete(t.entries, id)
+	}
+}
+
+// schedule will start the timer for the closing handler (or for the deadline if there is no handler)
+func (t *ConversionTracker) schedule(entry *trackedEntry, now time.Time) {
+	if t.options.onClosing == nil {
+		entry.timer = time.AfterFunc(entry.tracked.CancelDeadline.Sub(now), func() { t.expire(entry) })
+		return
+	}
+
+	// Already inside the warning window: the handler is called right away
+	warnAt := entry.tracked.CancelDeadline.Add(-t.options.warning)
+	entry.timer = time.AfterFunc(warnAt.Sub(now), func() { t.closing(entry) })
+}
+
+// closing will call the closing handler and wait for the deadline
+func (t *ConversionTracker) closing(entry *trackedEntry) {
+	t.mu.Lock()
+	if t.entries[entry.tracked.Conversion.ID] != entry {
+		t.mu.Unlock()
+		return
+	}
+	entry.timer = time.AfterFunc(entry.tracked.CancelDeadline.Sub(t.options.now()), func() { t.expire(entry) })
+	t.mu.Unlock()
+
+	t.options.onClosing(entry.tracked)
+}
+
+// expire will stop tracking the conversion and call the expired handler
+func (t *ConversionTracker) expire(entry *trackedEntry) {
+	t.mu.Lock()
+	if t.entries[entry.tracked.Conversion.ID] != entry {
+		t.mu.Unlock()
+		return
+	}
+	delete(t.entries, entry.tracked.Conversion.ID)
+	t.mu.Unlock()
+
+	if t.options.onExpired != nil {
+		t.options.onExpired(entry.tracked)
+	}
+}
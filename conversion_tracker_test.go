@@ -0,0 +1,195 @@
+package tonicpow
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testTrackerClient is a ConversionService for testing the tracker
+type testTrackerClient struct {
+	ConversionService
+	cancelErr error
+	canceled  []uint64
+	mu        sync.Mutex
+}
+
+// CreateConversionWithContext will return a new pending conversion
+func (c *testTrackerClient) CreateConversionWithContext(_ context.Context,
+	_ ...ConversionOps) (*Conversion, *StandardResponse, error) {
+	return &Conversion{ID: testConversionID, Status: "pending"}, nil, nil
+}
+
+// CancelConversionWithContext will record the canceled conversion
+func (c *testTrackerClient) CancelConversionWithContext(_ context.Context, conversionID uint64,
+	_ string) (*Conversion, *StandardResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancelErr != nil {
+		return nil, nil, c.cancelErr
+	}
+	c.canceled = append(c.canceled, conversionID)
+	return &Conversion{ID: conversionID, Status: "cancelled"}, nil, nil
+}
+
+// TestConversionTracker will test the ConversionTracker
+func TestConversionTracker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("deadline from payout after", func(t *testing.T) {
+		tracker := NewConversionTracker(&testTrackerClient{})
+		defer tracker.Close()
+
+		payoutAfter := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+		tracked, ok := tracker.Track(&Conversion{
+			ID:          testConversionID,
+			PayoutAfter: payoutAfter.Format(apiTimeFormat),
+		}, 0)
+		assert.True(t, ok)
+		assert.True(t, payoutAfter.Equal(tracked.PayoutAfter))
+		assert.True(t, payoutAfter.Add(-time.Minute).Equal(tracked.CancelDeadline))
+		assert.True(t, tracker.Cancellable(testConversionID))
+		assert.False(t, tracker.Cancellable(1))
+	})
+
+	t.Run("deadline from delay", func(t *testing.T) {
+		tracker := NewConversionTracker(&testTrackerClient{})
+		defer tracker.Close()
+
+		tracked, ok := tracker.Track(&Conversion{ID: testConversionID}, 10*time.Minute)
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(9*time.Minute), tracked.CancelDeadline, 5*time.Second)
+	})
+
+	t.Run("not cancellable conversions are not tracked", func(t *testing.T) {
+		tracker := NewConversionTracker(&testTrackerClient{})
+		defer tracker.Close()
+
+		_, ok := tracker.Track(&Conversion{ID: testConversionID}, 30*time.Second)
+		assert.False(t, ok)
+		_, ok = tracker.Track(&Conversion{ID: testConversionID, PayoutAfter: "2021-01-01 00:00:00"}, time.Hour)
+		assert.False(t, ok)
+		_, ok = tracker.Track(nil, time.Hour)
+		assert.False(t, ok)
+		assert.False(t, tracker.Cancellable(testConversionID))
+	})
+
+	t.Run("create conversion with a delay", func(t *testing.T) {
+		tracker := NewConversionTracker(&testTrackerClient{})
+		defer tracker.Close()
+
+		conversion, _, err := tracker.CreateConversion(
+			context.Background(), WithGoalID(testGoalID), WithUserID(testUserID), WithDelay(10),
+		)
+		assert.NoError(t, err)
+		assert.NotNil(t, conversion)
+		assert.True(t, tracker.Cancellable(testConversionID))
+
+		tracker.Untrack(testConversionID)
+		assert.False(t, tracker.Cancellable(testConversionID))
+
+		_, _, err = tracker.CreateConversion(context.Background(), WithGoalID(testGoalID), WithUserID(testUserID))
+		assert.NoError(t, err)
+		assert.False(t, tracker.Cancellable(testConversionID))
+	})
+
+	t.Run("cancel if pending", func(t *testing.T) {
+		client := &testTrackerClient{}
+		tracker := NewConversionTracker(client)
+		defer tracker.Close()
+
+		canceled, conversion, err := tracker.CancelIfPending(context.Background(), testConversionID, "refund")
+		assert.NoError(t, err)
+		assert.False(t, canceled)
+		assert.Nil(t, conversion)
+
+		tracker.Track(&Conversion{ID: testConversionID}, time.Hour)
+		canceled, conversion, err = tracker.CancelIfPending(context.Background(), testConversionID, "refund")
+		assert.NoError(t, err)
+		assert.True(t, canceled)
+		assert.Equal(t, ConversionStatusCancelled, conversion.Status)
+		assert.Equal(t, []uint64{testConversionID}, client.canceled)
+		assert.False(t, tracker.Cancellable(testConversionID))
+	})
+
+	t.Run("cancel error keeps tracking", func(t *testing.T) {
+		client := &testTrackerClient{cancelErr: &Error{Code: 400, StatusCode: http.StatusBadRequest}}
+		tracker := NewConversionTracker(client)
+		defer tracker.Close()
+
+		tracker.Track(&Conversion{ID: testConversionID}, time.Hour)
+		canceled, _, err := tracker.CancelIfPending(context.Background(), testConversionID, "refund")
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.False(t, canceled)
+		assert.True(t, tracker.Cancellable(testConversionID))
+	})
+
+	t.Run("closing and expired handlers", func(t *testing.T) {
+		now := time.Now()
+		closing := make(chan TrackedConversion, 1)
+		expired := make(chan TrackedConversion, 1)
+		tracker := NewConversionTracker(
+			&testTrackerClient{},
+			WithClosingHandler(50*time.Millisecond, func(tracked TrackedConversion) { closing <- tracked }),
+			WithExpiredHandler(func(tracked TrackedConversion) { expired <- tracked }),
+		)
+		defer tracker.Close()
+
+		// Deadline is 100ms away
+		tracker.Track(&Conversion{ID: testConversionID}, time.Minute+100*time.Millisecond)
+
+		select {
+		case tracked := <-closing:
+			assert.Equal(t, testConversionID, tracked.Conversion.ID)
+			assert.GreaterOrEqual(t, time.Since(now), 40*time.Millisecond)
+			assert.True(t, tracker.Cancellable(testConversionID))
+		case <-time.After(5 * time.Second):
+			t.Fatal("closing handler was not called")
+		}
+
+		select {
+		case tracked := <-expired:
+			assert.Equal(t, testConversionID, tracked.Conversion.ID)
+			assert.False(t, tracker.Cancellable(testConversionID))
+		case <-time.After(5 * time.Second):
+			t.Fatal("expired handler was not called")
+		}
+	})
+
+	t.Run("inside the warning window", func(t *testing.T) {
+		called := make(chan struct{}, 1)
+		tracker := NewConversionTracker(
+			&testTrackerClient{},
+			WithClosingHandler(time.Hour, func(TrackedConversion) { called <- struct{}{} }),
+		)
+		defer tracker.Close()
+
+		tracker.Track(&Conversion{ID: testConversionID}, 2*time.Minute)
+		select {
+		case <-called:
+		case <-time.After(5 * time.Second):
+			t.Fatal("closing handler was not called")
+		}
+	})
+
+	t.Run("closed tracker does not call handlers", func(t *testing.T) {
+		called := make(chan struct{}, 1)
+		tracker := NewConversionTracker(
+			&testTrackerClient{},
+			WithExpiredHandler(func(TrackedConversion) { called <- struct{}{} }),
+		)
+		tracker.Track(&Conversion{ID: testConversionID}, time.Minute+50*time.Millisecond)
+		tracker.Close()
+		assert.False(t, tracker.Cancellable(testConversionID))
+
+		select {
+		case <-called:
+			t.Fatal("handler called after close")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package tonicpow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -12,26 +13,29 @@ type ConversionOps func(c *conversionOptions)
 
 // conversionOptions holds all the configuration for the conversion
 type conversionOptions struct {
-	customDimensions string  // (optional) custom dimensions to add to the conversion
-	delayInMinutes   uint64  // (optional) delay the conversion x minutes (before processing, allowing cancellation)
-	goalID           uint64  // Goal by ID
-	goalName         string  // Goal by name
-	purchaseAmount   float64 // (optional) purchase amount (total for e-commerce)
-	shortCode        string  // (optional) trigger a conversion for a link short_code
-	tncpwSession     string  // tncpw session
-	tonicPowUserID   uint64  // (optional) trigger a conversion for a specific user
-	twitterID        string  // (optional) trigger a conversion for a specific Twitter user
+	customDimensions string // (optional) custom dimensions to add to the conversion
+	dimensionsErr    error  // error from encoding the custom dimensions (returned by validate)
+	delayInMinutes   uint64 // (optional) delay the conversion x minutes (before processing, allowing cancellation)
+	goalID           uint64 // Goal by ID
+	goalName         string // Goal by name
+	idempotencyKey   string // (optional) idempotency key (safe to retry / replay the conversion)
+	purchaseAmount   Money  // (optional) purchase amount (total for e-commerce, sent exactly)
+	shortCode        string // (optional) trigger a conversion for a link short_code
+	tncpwSession     string // tncpw session
+	tonicPowUserID   uint64 // (optional) trigger a conversion for a specific user
+	twitterID        string // (optional) trigger a conversion for a specific Twitter user
 }
 
 // validate will check the options before processing
 func (o *conversionOptions) validate() error {
-	if o.goalID == 0 && len(o.goalName) == 0 {
-		return fmt.Errorf("missing required attribute(s): %s or %s", fieldID, fieldName)
+	if o.dimensionsErr != nil {
+		return o.dimensionsErr
+	} else if o.goalID == 0 && len(o.goalName) == 0 {
+		return newMissingAttributeError(fieldID, fieldName)
 	} else if o.goalID == 0 && o.tonicPowUserID > 0 {
-		return fmt.Errorf("missing required attribute: %s", fieldID)
+		return newMissingAttributeError(fieldID)
 	} else if o.tonicPowUserID == 0 && len(o.tncpwSession) == 0 && len(o.shortCode) == 0 && len(o.twitterID) == 0 {
-		return fmt.Errorf(
-			"missing required attribute(s): %s or %s or %s or %s",
+		return newMissingAttributeError(
 			fieldVisitorSessionGUID, fieldUserID, fieldShortCode, fieldTwitterID,
 		)
 	}
@@ -69,8 +73,8 @@ func (o *conversionOptions) payload() map[string]string {
 	}
 
 	// Set purchase amount
-	if o.purchaseAmount > 0 {
-		m[fieldAmount] = fmt.Sprintf("%f", o.purchaseAmount)
+	if o.purchaseAmount.Cmp(Money{}) > 0 {
+		m[fieldAmount] = o.purchaseAmount.Text()
 	}
 
 	// Set custom dimensions
@@ -95,6 +99,16 @@ func WithGoalName(name string) ConversionOps {
 	}
 }
 
+// WithIdempotencyKey will set an idempotency key for the conversion
+//
+// Persist the key (IE: alongside the order ID) and reuse it if the conversion is sent again,
+// the conversion will only be created once (see: NewIdempotencyKey)
+func WithIdempotencyKey(key string) ConversionOps {
+	return func(c *conversionOptions) {
+		c.idempotencyKey = key
+	}
+}
+
 // WithTncpwSession will set a tncpw_session
 func WithTncpwSession(session string) ConversionOps {
 	return func(c *conversionOptions) {
@@ -103,14 +117,24 @@ func WithTncpwSession(session string) ConversionOps {
 }
 
 // WithCustomDimensions will set custom dimensions (string / json)
+//
+// See WithCustomDimensionsMap and WithCustomDimensionsStruct for encoding & validation
 func WithCustomDimensions(dimensions string) ConversionOps {
 	return func(c *conversionOptions) {
 		c.customDimensions = dimensions
+		c.dimensionsErr = nil
 	}
 }
 
-// WithPurchaseAmount will set purchase amount from e-commerce
+// WithPurchaseAmount will set purchase amount from e-commerce (rounded to 8 decimal places)
 func WithPurchaseAmount(amount float64) ConversionOps {
+	return func(c *conversionOptions) {
+		c.purchaseAmount = MoneyFromFloat(amount, "")
+	}
+}
+
+// WithPurchaseAmountMoney will set purchase amount from e-commerce (exact, see: Money)
+func WithPurchaseAmountMoney(amount Money) ConversionOps {
 	return func(c *conversionOptions) {
 		c.purchaseAmount = amount
 	}
@@ -149,6 +173,12 @@ func WithTwitterID(twitterID string) ConversionOps {
 // For more information: https://docs.tonicpow.com/#caeffdd5-eaad-4fc8-ac01-8288b50e8e27
 func (c *Client) CreateConversion(opts ...ConversionOps) (conversion *Conversion,
 	response *StandardResponse, err error) {
+	return c.CreateConversionWithContext(context.Background(), opts...)
+}
+
+// CreateConversionWithContext is the same as CreateConversion, but uses the given context for the request
+func (c *Client) CreateConversionWithContext(ctx context.Context, opts ...ConversionOps) (conversion *Conversion,
+	response *StandardResponse, err error) {
 
 	// Start the options
 	options := new(conversionOptions)
@@ -163,9 +193,15 @@ func (c *Client) CreateConversion(opts ...ConversionOps) (conversion *Conversion
 		return
 	}
 
+	// Set the idempotency key (used on all attempts)
+	if len(options.idempotencyKey) > 0 {
+		ctx = ContextWithIdempotencyKey(ctx, options.idempotencyKey)
+	}
+
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodPost,
+	ctx = withOperation(ctx, Operation{Name: "CreateConversion", GoalID: options.goalID})
+	if response, err = c.RequestWithContext(
+		ctx, http.MethodPost,
 		"/"+modelConversion,
 		options.payload(), http.StatusCreated,
 	); err != nil {
@@ -182,16 +218,23 @@ func (c *Client) CreateConversion(opts ...ConversionOps) (conversion *Conversion
 // For more information: https://docs.tonicpow.com/#fce465a1-d8d5-442d-be22-95169170167e
 func (c *Client) GetConversion(conversionID uint64) (conversion *Conversion,
 	response *StandardResponse, err error) {
+	return c.GetConversionWithContext(context.Background(), conversionID)
+}
+
+// GetConversionWithContext is the same as GetConversion, but uses the given context for the request
+func (c *Client) GetConversionWithContext(ctx context.Context, conversionID uint64) (conversion *Conversion,
+	response *StandardResponse, err error) {
 
 	// Must have an ID
 	if conversionID == 0 {
-		err = fmt.Errorf("missing required attribute: %s", fieldID)
+		err = newMissingAttributeError(fieldID)
 		return
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
+	ctx = withOperation(ctx, Operation{Name: "GetConversion", ConversionID: conversionID})
+	if response, err = c.RequestWithContext(
+		ctx, http.MethodGet,
 		fmt.Sprintf("/%s/details/%d", modelConversion, conversionID),
 		nil, http.StatusOK,
 	); err != nil {
@@ -207,16 +250,23 @@ func (c *Client) GetConversion(conversionID uint64) (conversion *Conversion,
 // For more information: https://docs.tonicpow.com/#e650b083-bbb4-4ff7-9879-c14b1ab3f753
 func (c *Client) CancelConversion(conversionID uint64, cancelReason string) (conversion *Conversion,
 	response *StandardResponse, err error) {
+	return c.CancelConversionWithContext(context.Background(), conversionID, cancelReason)
+}
+
+// CancelConversionWithContext is the same as CancelConversion, but uses the given context for the request
+func (c *Client) CancelConversionWithContext(ctx context.Context, conversionID uint64, cancelReason string) (conversion *Conversion,
+	response *StandardResponse, err error) {
 
 	// Must have an ID
 	if conversionID == 0 {
-		err = fmt.Errorf("missing required attribute: %s", fieldID)
+		err = newMissingAttributeError(fieldID)
 		return
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodPut,
+	ctx = withOperation(ctx, Operation{Name: "CancelConversion", ConversionID: conversionID})
+	if response, err = c.RequestWithContext(
+		ctx, http.MethodPut,
 		fmt.Sprintf("/%s/cancel", modelConversion),
 		map[string]string{
 			fieldID:     fmt.Sprintf("%d", conversionID),
@@ -1,10 +1,12 @@
 package tonicpow
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
 
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -104,6 +106,41 @@ func TestClient_CreateConversion(t *testing.T) {
 		assert.Equal(t, testConversionID, newConversion.ID)
 	})
 
+	t.Run("purchase amount is sent as an exact decimal", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelConversion)
+		var sent string
+		httpmock.RegisterResponder(http.MethodPost, endpoint, func(req *http.Request) (*http.Response, error) {
+			var payload map[string]string
+			if decodeErr := json.NewDecoder(req.Body).Decode(&payload); decodeErr != nil {
+				return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+			}
+			sent = payload[fieldAmount]
+			return httpmock.NewJsonResponse(http.StatusCreated, newTestConversion())
+		})
+
+		// Trailing zeros are trimmed (previously sent as "%f": "120.000000")
+		_, _, err = client.CreateConversion(
+			WithGoalID(testGoalID),
+			WithTncpwSession(testTncpwSession),
+			WithPurchaseAmount(120.00),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, "120", sent)
+
+		// Up to 8 decimal places (previously rounded to 6: "0.123457")
+		_, _, err = client.CreateConversion(
+			WithGoalID(testGoalID),
+			WithTncpwSession(testTncpwSession),
+			WithPurchaseAmount(0.12345678),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.12345678", sent)
+	})
+
 	t.Run("create a conversion by goal id / user id (success)", func(t *testing.T) {
 		client, err := newTestClient()
 		assert.NoError(t, err)
@@ -0,0 +1,267 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrInvalidRate is returned when a rate cannot be used for conversions (IE: zero price)
+var ErrInvalidRate = errors.New("rate is not valid for conversions")
+
+// RoundingMode is how amounts are rounded when converting between currencies
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest value, halves away from zero (1.5 => 2, -1.5 => -2)
+	RoundHalfUp RoundingMode = iota
+
+	// RoundHalfEven rounds to the nearest value, halves to the even value (1.5 => 2, 2.5 => 2)
+	RoundHalfEven
+
+	// RoundDown rounds toward zero (IE: never spend more than a budget)
+	RoundDown
+
+	// RoundUp rounds away from zero (IE: always cover a cost)
+	RoundUp
+)
+
+// String will return the name of the rounding mode
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundHalfUp:
+		return "half_up"
+	case RoundHalfEven:
+		return "half_even"
+	case RoundDown:
+		return "down"
+	case RoundUp:
+		return "up"
+	}
+	return fmt.Sprintf("RoundingMode(%d)", int(m))
+}
+
+// ToSatoshis will convert the amount to satoshis (rounded half up)
+//
+// The amount must be in the rate currency (or BSV, or have no currency)
+func (r *Rate) ToSatoshis(amount Money) (Satoshis, error) {
+	return r.ToSatoshisRounded(amount, RoundHalfUp)
+}
+
+// ToSatoshisRounded is the same as ToSatoshis, but uses the rounding mode
+func (r *Rate) ToSatoshisRounded(amount Money, mode RoundingMode) (Satoshis, error) {
+	if amount.currency == CurrencyBSV {
+		return Satoshis(amount.units), nil
+	}
+	if err := r.check(amount.currency); err != nil {
+		return 0, err
+	}
+	// sats = amount * price / currency amount
+	num := new(big.Int).Mul(big.NewInt(amount.units), big.NewInt(r.PriceInSatoshis))
+	sats := divRound(num, big.NewInt(r.currencyUnits()), mode)
+	if !sats.IsInt64() {
+		return 0, ErrMoneyOverflow
+	}
+	return Satoshis(sats.Int64()), nil
+}
+
+// FromSatoshis will convert the satoshis to the rate currency (rounded half up to 8 decimal places)
+//
+// Use Money.Round to round to the currency (IE: cents)
+func (r *Rate) FromSatoshis(sats Satoshis) (Money, error) {
+	return r.FromSatoshisRounded(sats, RoundHalfUp)
+}
+
+// FromSatoshisRounded is the same as FromSatoshis, but uses the rounding mode
+func (r *Rate) FromSatoshisRounded(sats Satoshis, mode RoundingMode) (Money, error) {
+	if err := r.check(""); err != nil {
+		return Money{}, err
+	}
+	// amount = sats * currency amount / price
+	num := new(big.Int).Mul(big.NewInt(int64(sats)), big.NewInt(r.currencyUnits()))
+	units := divRound(num, big.NewInt(r.PriceInSatoshis), mode)
+	if !units.IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{currency: strings.ToLower(r.Currency), units: units.Int64()}, nil
+}
+
+// ConvertTo will convert the amount from the rate currency to the currency of the other rate
+// (rounded half up to 8 decimal places)
+//
+// The amount is converted through satoshis without rounding in between
+func (r *Rate) ConvertTo(amount Money, to *Rate) (Money, error) {
+	return r.ConvertToRounded(amount, to, RoundHalfUp)
+}
+
+// ConvertToRounded is the same as ConvertTo, but uses the rounding mode
+func (r *Rate) ConvertToRounded(amount Money, to *Rate, mode RoundingMode) (Money, error) {
+	if err := r.check(amount.currency); err != nil {
+		return Money{}, err
+	} else if err = to.check(""); err != nil {
+		return Money{}, err
+	}
+	// amount * price / currency amount * to currency amount / to price
+	num := new(big.Int).Mul(big.NewInt(amount.units), big.NewInt(r.PriceInSatoshis))
+	num.Mul(num, big.NewInt(to.currencyUnits()))
+	den := new(big.Int).Mul(big.NewInt(r.currencyUnits()), big.NewInt(to.PriceInSatoshis))
+	units := divRound(num, den, mode)
+	if !units.IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{currency: strings.ToLower(to.Currency), units: units.Int64()}, nil
+}
+
+// check will return an error if the rate cannot convert amounts in the currency
+func (r *Rate) check(currency string) error {
+	if r == nil || r.PriceInSatoshis <= 0 || r.currencyUnits() <= 0 {
+		return ErrInvalidRate
+	} else if len(currency) > 0 && !strings.EqualFold(currency, r.Currency) {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, currency, strings.ToLower(r.Currency))
+	}
+	return nil
+}
+
+// currencyUnits will return the currency amount in units of 10^-8
+func (r *Rate) currencyUnits() int64 {
+	return MoneyFromFloat(r.CurrencyAmount, "").units
+}
+
+// divRound will return num / den rounded with the mode (den must be positive)
+func divRound(num, den *big.Int, mode RoundingMode) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	// Direction away from zero
+	step := big.NewInt(int64(num.Sign()))
+	switch mode {
+	case RoundDown:
+		return quo
+	case RoundUp:
+		return quo.Add(quo, step)
+	case RoundHalfUp, RoundHalfEven:
+		half := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(den)
+		if half > 0 || (half == 0 && (mode == RoundHalfUp || quo.Bit(0) == 1)) {
+			return quo.Add(quo, step)
+		}
+	}
+	return quo
+}
+
+// ConverterOps allow functional options to be supplied
+// that overwrite default converter options.
+type ConverterOps func(o *converterOptions)
+
+// converterOptions holds all the configuration for the converter
+type converterOptions struct {
+	rateAmount float64      // Amount requested with GetCurrentRate
+	rounding   RoundingMode // Rounding mode for all conversions
+}
+
+// WithRoundingMode will set the rounding mode for all conversions
+// Default is RoundHalfUp.
+func WithRoundingMode(mode RoundingMode) ConverterOps {
+	return func(o *converterOptions) {
+		o.rounding = mode
+	}
+}
+
+// WithConverterRateAmount will set the amount requested with GetCurrentRate (a larger amount is a more precise rate)
+// Default is 0 (the API default amount).
+func WithConverterRateAmount(amount float64) ConverterOps {
+	return func(o *converterOptions) {
+		o.rateAmount = amount
+	}
+}
+
+// Converter converts amounts between currencies & satoshis using the current rates
+//
+// Rates are cached (see: RateCache), all conversions use the same rounding mode so
+// budget & payout math is consistent.
+type Converter struct {
+	options *converterOptions
+	rates   RateService
+}
+
+// NewConverter will return a new converter using the rates
+//
+// The rates are wrapped in a RateCache with the default options (unless already a *RateCache)
+func NewConverter(rates RateService, opts ...ConverterOps) *Converter {
+	options := &converterOptions{rounding: RoundHalfUp}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if _, ok := rates.(*RateCache); !ok {
+		rates = NewRateCache(rates)
+	}
+	return &Converter{options: options, rates: rates}
+}
+
+// RoundingMode will return the rounding mode of the converter
+func (c *Converter) RoundingMode() RoundingMode {
+	return c.options.rounding
+}
+
+// Rate will return the current rate for the currency
+func (c *Converter) Rate(ctx context.Context, currency string) (*Rate, error) {
+	if len(currency) == 0 {
+		return nil, newMissingAttributeError(fieldCurrency)
+	}
+	rate, _, err := c.rates.GetCurrentRateWithContext(ctx, strings.ToLower(currency), c.options.rateAmount)
+	return rate, err
+}
+
+// ToSatoshis will convert the amount to satoshis
+func (c *Converter) ToSatoshis(ctx context.Context, amount Money) (Satoshis, error) {
+	if amount.currency == CurrencyBSV {
+		return Satoshis(amount.units), nil
+	}
+	rate, err := c.Rate(ctx, amount.currency)
+	if err != nil {
+		return 0, err
+	}
+	return rate.ToSatoshisRounded(amount, c.options.rounding)
+}
+
+// FromSatoshis will convert the satoshis to the currency
+func (c *Converter) FromSatoshis(ctx context.Context, sats Satoshis, currency string) (Money, error) {
+	if strings.EqualFold(currency, CurrencyBSV) {
+		return sats.BSV(), nil
+	}
+	rate, err := c.Rate(ctx, currency)
+	if err != nil {
+		return Money{}, err
+	}
+	return rate.FromSatoshisRounded(sats, c.options.rounding)
+}
+
+// Convert will convert the amount to the currency
+func (c *Converter) Convert(ctx context.Context, amount Money, currency string) (Money, error) {
+	currency = strings.ToLower(currency)
+	switch {
+	case amount.currency == currency:
+		return amount, nil
+	case currency == CurrencyBSV:
+		sats, err := c.ToSatoshis(ctx, amount)
+		if err != nil {
+			return Money{}, err
+		}
+		return sats.BSV(), nil
+	case amount.currency == CurrencyBSV:
+		return c.FromSatoshis(ctx, Satoshis(amount.units), currency)
+	}
+
+	from, err := c.Rate(ctx, amount.currency)
+	if err != nil {
+		return Money{}, err
+	}
+	var to *Rate
+	if to, err = c.Rate(ctx, currency); err != nil {
+		return Money{}, err
+	}
+	return from.ConvertToRounded(amount, to, c.options.rounding)
+}
@@ -0,0 +1,301 @@
+package tonicpow
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRates is a RateService with a fixed rate per currency
+type testRates struct {
+	calls map[string]int
+	mu    sync.Mutex
+	rates map[string]*Rate
+}
+
+// GetCurrentRate will return the rate for the currency
+func (s *testRates) GetCurrentRate(currency string, amount float64) (*Rate, *StandardResponse, error) {
+	return s.GetCurrentRateWithContext(context.Background(), currency, amount)
+}
+
+// GetCurrentRateWithContext will return the rate for the currency
+func (s *testRates) GetCurrentRateWithContext(_ context.Context, currency string,
+	_ float64) (*Rate, *StandardResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.calls == nil {
+		s.calls = make(map[string]int)
+	}
+	s.calls[currency]++
+	rate, ok := s.rates[currency]
+	if !ok {
+		return nil, nil, &Error{StatusCode: http.StatusNotFound, Message: "currency not found"}
+	}
+	return rate, nil, nil
+}
+
+// newTestRates will return rates for usd (1 USD = 2,000,000 sats) and eur (1 EUR = 2,500,000 sats)
+func newTestRates() *testRates {
+	return &testRates{rates: map[string]*Rate{
+		CurrencyUSD: {Currency: CurrencyUSD, CurrencyAmount: 0.01, PriceInSatoshis: 20000},
+		CurrencyEUR: {Currency: CurrencyEUR, CurrencyAmount: 1, PriceInSatoshis: 2500000},
+	}}
+}
+
+// TestDivRound will test the rounding modes
+func TestDivRound(t *testing.T) {
+	t.Parallel()
+
+	// value is num / 10
+	tests := []struct {
+		num      int64
+		mode     RoundingMode
+		expected int64
+	}{
+		{15, RoundHalfUp, 2},
+		{-15, RoundHalfUp, -2},
+		{14, RoundHalfUp, 1},
+		{25, RoundHalfEven, 2},
+		{15, RoundHalfEven, 2},
+		{-25, RoundHalfEven, -2},
+		{26, RoundHalfEven, 3},
+		{19, RoundDown, 1},
+		{-19, RoundDown, -1},
+		{11, RoundUp, 2},
+		{-11, RoundUp, -2},
+		{20, RoundUp, 2},
+	}
+	for _, test := range tests {
+		result := divRound(bigInt(test.num), bigInt(10), test.mode)
+		assert.Equal(t, test.expected, result.Int64(), "%d / 10 %s", test.num, test.mode)
+	}
+	assert.Equal(t, "half_even", RoundHalfEven.String())
+	assert.Equal(t, "RoundingMode(9)", RoundingMode(9).String())
+}
+
+// TestRate_ToSatoshis will test the methods ToSatoshis() and FromSatoshis()
+func TestRate_ToSatoshis(t *testing.T) {
+	t.Parallel()
+
+	rate := newTestRates().rates[CurrencyUSD]
+
+	t.Run("to satoshis", func(t *testing.T) {
+		sats, err := rate.ToSatoshis(mustParseMoney(t, "19.99", CurrencyUSD))
+		assert.NoError(t, err)
+		assert.Equal(t, Satoshis(39980000), sats)
+
+		// 0.00000075 USD = 1.5 sats
+		sats, err = rate.ToSatoshis(mustParseMoney(t, "0.00000075", ""))
+		assert.NoError(t, err)
+		assert.Equal(t, Satoshis(2), sats)
+
+		sats, err = rate.ToSatoshisRounded(mustParseMoney(t, "0.00000075", CurrencyUSD), RoundDown)
+		assert.NoError(t, err)
+		assert.Equal(t, Satoshis(1), sats)
+
+		sats, err = rate.ToSatoshis(Satoshis(4200).BSV())
+		assert.NoError(t, err)
+		assert.Equal(t, Satoshis(4200), sats)
+	})
+
+	t.Run("from satoshis", func(t *testing.T) {
+		m, err := rate.FromSatoshis(39980000)
+		assert.NoError(t, err)
+		assert.Equal(t, "19.99", m.Text())
+		assert.Equal(t, CurrencyUSD, m.Currency())
+
+		// 1 sat = 0.0000005 USD
+		m, err = rate.FromSatoshis(1)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.0000005", m.Text())
+
+		// 1 sat = 3/7 USD (0.428571428...)
+		m, err = (&Rate{Currency: "USD", CurrencyAmount: 3, PriceInSatoshis: 7}).FromSatoshisRounded(1, RoundUp)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.42857143", m.Text())
+
+		m, err = (&Rate{Currency: "USD", CurrencyAmount: 3, PriceInSatoshis: 7}).FromSatoshisRounded(1, RoundDown)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.42857142", m.Text())
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, amount := range []string{"0.01", "1", "19.99", "1234.56"} {
+			sats, err := rate.ToSatoshis(mustParseMoney(t, amount, CurrencyUSD))
+			assert.NoError(t, err)
+			var m Money
+			m, err = rate.FromSatoshis(sats)
+			assert.NoError(t, err)
+			assert.Equal(t, amount, m.Text())
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := rate.ToSatoshis(mustParseMoney(t, "1", CurrencyEUR))
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+		_, err = (&Rate{Currency: CurrencyUSD}).ToSatoshis(mustParseMoney(t, "1", CurrencyUSD))
+		assert.ErrorIs(t, err, ErrInvalidRate)
+
+		var nilRate *Rate
+		_, err = nilRate.FromSatoshis(1)
+		assert.ErrorIs(t, err, ErrInvalidRate)
+
+		_, err = (&Rate{Currency: CurrencyUSD, CurrencyAmount: 0.00000001, PriceInSatoshis: 1 << 40}).
+			ToSatoshis(mustParseMoney(t, "90000000000", CurrencyUSD))
+		assert.ErrorIs(t, err, ErrMoneyOverflow)
+	})
+}
+
+// TestRate_ConvertTo will test the method ConvertTo()
+func TestRate_ConvertTo(t *testing.T) {
+	t.Parallel()
+
+	rates := newTestRates()
+	usd, eur := rates.rates[CurrencyUSD], rates.rates[CurrencyEUR]
+
+	m, err := usd.ConvertTo(mustParseMoney(t, "10", CurrencyUSD), eur)
+	assert.NoError(t, err)
+	assert.Equal(t, "8", m.Text())
+	assert.Equal(t, CurrencyEUR, m.Currency())
+
+	m, err = eur.ConvertTo(mustParseMoney(t, "10", CurrencyEUR), usd)
+	assert.NoError(t, err)
+	assert.Equal(t, "12.5", m.Text())
+
+	// 1 USD = 0.8 EUR exactly, no rounding through satoshis
+	m, err = usd.ConvertToRounded(mustParseMoney(t, "0.00000001", CurrencyUSD), eur, RoundDown)
+	assert.NoError(t, err)
+	assert.Equal(t, "0", m.Text())
+
+	m, err = usd.ConvertToRounded(mustParseMoney(t, "0.00000001", CurrencyUSD), eur, RoundUp)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.00000001", m.Text())
+
+	_, err = usd.ConvertTo(mustParseMoney(t, "10", CurrencyEUR), eur)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = usd.ConvertTo(mustParseMoney(t, "10", CurrencyUSD), &Rate{})
+	assert.ErrorIs(t, err, ErrInvalidRate)
+}
+
+// TestConverter will test the Converter
+func TestConverter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("conversions", func(t *testing.T) {
+		rates := newTestRates()
+		converter := NewConverter(rates)
+		assert.Equal(t, RoundHalfUp, converter.RoundingMode())
+		ctx := context.Background()
+
+		sats, err := converter.ToSatoshis(ctx, mustParseMoney(t, "1", CurrencyUSD))
+		assert.NoError(t, err)
+		assert.Equal(t, Satoshis(2000000), sats)
+
+		var m Money
+		m, err = converter.FromSatoshis(ctx, 2500000, "EUR")
+		assert.NoError(t, err)
+		assert.Equal(t, "€1.00", m.Format())
+
+		m, err = converter.FromSatoshis(ctx, 4200, CurrencyBSV)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.000042", m.Text())
+
+		m, err = converter.Convert(ctx, mustParseMoney(t, "10", CurrencyUSD), "EUR")
+		assert.NoError(t, err)
+		assert.Equal(t, "8", m.Text())
+
+		m, err = converter.Convert(ctx, mustParseMoney(t, "1", CurrencyUSD), CurrencyBSV)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.02", m.Text())
+
+		m, err = converter.Convert(ctx, m, CurrencyUSD)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", m.Text())
+
+		m, err = converter.Convert(ctx, mustParseMoney(t, "3", CurrencyUSD), CurrencyUSD)
+		assert.NoError(t, err)
+		assert.Equal(t, "3", m.Text())
+
+		// Rates are cached
+		assert.Equal(t, 1, rates.calls[CurrencyUSD])
+		assert.Equal(t, 1, rates.calls[CurrencyEUR])
+	})
+
+	t.Run("rounding mode", func(t *testing.T) {
+		converter := NewConverter(newTestRates(), WithRoundingMode(RoundDown))
+		sats, err := converter.ToSatoshis(context.Background(), mustParseMoney(t, "0.00000075", CurrencyUSD))
+		assert.NoError(t, err)
+		assert.Equal(t, Satoshis(1), sats)
+	})
+
+	t.Run("uses an existing cache", func(t *testing.T) {
+		cache := NewRateCache(newTestRates())
+		converter := NewConverter(cache)
+		assert.Equal(t, cache, converter.rates)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		converter := NewConverter(newTestRates())
+		ctx := context.Background()
+
+		_, err := converter.ToSatoshis(ctx, mustParseMoney(t, "1", ""))
+		assert.ErrorIs(t, err, ErrValidation)
+
+		_, err = converter.ToSatoshis(ctx, mustParseMoney(t, "1", "xyz"))
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		_, err = converter.FromSatoshis(ctx, 1, "xyz")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		_, err = converter.Convert(ctx, mustParseMoney(t, "1", CurrencyUSD), "xyz")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		_, err = converter.Convert(ctx, mustParseMoney(t, "1", "xyz"), CurrencyUSD)
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		_, err = converter.Convert(ctx, mustParseMoney(t, "1", "xyz"), CurrencyBSV)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+// TestConverter_Client will test the Converter with the Client
+func TestConverter_Client(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	client, err := newTestClient()
+	assert.NoError(t, err)
+	assert.NotNil(t, client)
+
+	endpoint := fmt.Sprintf(
+		"%s/%s/%s?%s=%f", EnvironmentDevelopment.apiURL,
+		modelRates, testRateCurrency,
+		fieldAmount, 100.0,
+	)
+	err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, &Rate{
+		Currency: testRateCurrency, CurrencyAmount: 100, PriceInSatoshis: 200000000,
+	})
+	assert.NoError(t, err)
+	httpmock.ZeroCallCounters()
+
+	converter := NewConverter(client, WithConverterRateAmount(100))
+	for i := 0; i < 3; i++ {
+		var sats Satoshis
+		sats, err = converter.ToSatoshis(context.Background(), mustParseMoney(t, "0.25", testRateCurrency))
+		assert.NoError(t, err)
+		assert.Equal(t, Satoshis(500000), sats)
+	}
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+// bigInt will return the value as a *big.Int
+func bigInt(v int64) *big.Int {
+	return big.NewInt(v)
+}
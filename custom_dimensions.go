@@ -0,0 +1,95 @@
+package tonicpow
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// WithCustomDimensionsMap will set custom dimensions from a map (encoded as a JSON object)
+//
+// Keys must be 1-64 characters (letters, numbers, "_", "-" or ".") and the
+// encoded JSON must be at most 4096 bytes, otherwise the conversion will
+// return a ValidationError
+func WithCustomDimensionsMap(dimensions map[string]any) ConversionOps {
+	return func(c *conversionOptions) {
+		c.customDimensions, c.dimensionsErr = encodeCustomDimensions(dimensions)
+	}
+}
+
+// WithCustomDimensionsStruct will set custom dimensions from a struct (or any value that encodes to a JSON object)
+//
+// The value is encoded with encoding/json (use json tags to name the keys),
+// the same validation as WithCustomDimensionsMap applies
+func WithCustomDimensionsStruct(v any) ConversionOps {
+	return func(c *conversionOptions) {
+		c.customDimensions, c.dimensionsErr = encodeCustomDimensions(v)
+	}
+}
+
+// DecodeCustomDimensions will decode the custom dimensions (JSON) into v
+//
+// v should be a pointer (IE: *map[string]any or a pointer to a struct),
+// v is left unchanged if the conversion has no custom dimensions
+func (c *Conversion) DecodeCustomDimensions(v any) error {
+	if len(c.CustomDimensions) == 0 {
+		return nil
+	}
+	return json.Unmarshal([]byte(c.CustomDimensions), v)
+}
+
+// encodeCustomDimensions will encode and validate the custom dimensions
+func encodeCustomDimensions(v any) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return "", newInvalidCustomDimensionsError(err.Error())
+	}
+
+	// Must be a JSON object (null is treated as no dimensions)
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(encoded, &fields); err != nil {
+		return "", newInvalidCustomDimensionsError("must be a JSON object")
+	} else if fields == nil {
+		return "", nil
+	}
+
+	// Check the keys (sorted for a consistent error)
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !isValidDimensionKey(key) {
+			return "", newInvalidCustomDimensionsError(fmt.Sprintf("key %q is not valid", key))
+		}
+	}
+
+	// Check the size
+	if len(encoded) > maxCustomDimensionsSize {
+		return "", newInvalidCustomDimensionsError(
+			fmt.Sprintf("%d bytes exceeds the maximum of %d bytes", len(encoded), maxCustomDimensionsSize),
+		)
+	}
+
+	return string(encoded), nil
+}
+
+// isValidDimensionKey will return true if the key is a valid custom dimension key
+func isValidDimensionKey(key string) bool {
+	if len(key) == 0 || len(key) > maxCustomDimensionsKeyLength {
+		return false
+	}
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,199 @@
+package tonicpow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// testDimensions is a struct for testing custom dimensions
+type testDimensions struct {
+	Coupon  string  `json:"coupon,omitempty"`
+	OrderID string  `json:"order_id"`
+	Total   float64 `json:"total"`
+}
+
+// TestWithCustomDimensionsMap will test the method WithCustomDimensionsMap()
+func TestWithCustomDimensionsMap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid map", func(t *testing.T) {
+		options := new(conversionOptions)
+		WithCustomDimensionsMap(map[string]any{"order_id": "A-100", "items": 2, "vip": true})(options)
+		assert.NoError(t, options.dimensionsErr)
+		assert.Equal(t, `{"items":2,"order_id":"A-100","vip":true}`, options.customDimensions)
+	})
+
+	t.Run("nil and empty map", func(t *testing.T) {
+		options := new(conversionOptions)
+		WithCustomDimensionsMap(nil)(options)
+		assert.NoError(t, options.dimensionsErr)
+		assert.Equal(t, "", options.customDimensions)
+
+		WithCustomDimensionsMap(map[string]any{})(options)
+		assert.NoError(t, options.dimensionsErr)
+		assert.Equal(t, "{}", options.customDimensions)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		for _, key := range []string{"", "order id", "order/id", strings.Repeat("k", maxCustomDimensionsKeyLength+1)} {
+			options := new(conversionOptions)
+			WithCustomDimensionsMap(map[string]any{key: 1})(options)
+			assert.ErrorIs(t, options.dimensionsErr, ErrValidation, key)
+			assert.Equal(t, "", options.customDimensions)
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		options := new(conversionOptions)
+		WithCustomDimensionsMap(map[string]any{"note": strings.Repeat("x", maxCustomDimensionsSize)})(options)
+		assert.ErrorIs(t, options.dimensionsErr, ErrValidation)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, options.dimensionsErr, &validationErr)
+		assert.Equal(t, fieldCustomDimensions, validationErr.Field)
+	})
+
+	t.Run("cannot encode", func(t *testing.T) {
+		options := new(conversionOptions)
+		WithCustomDimensionsMap(map[string]any{"callback": func() {}})(options)
+		assert.ErrorIs(t, options.dimensionsErr, ErrValidation)
+	})
+
+	t.Run("string form replaces the error", func(t *testing.T) {
+		options := new(conversionOptions)
+		WithCustomDimensionsMap(map[string]any{"bad key": 1})(options)
+		WithCustomDimensions(`{"order_id":"A-100"}`)(options)
+		assert.NoError(t, options.dimensionsErr)
+		assert.Equal(t, `{"order_id":"A-100"}`, options.customDimensions)
+	})
+}
+
+// TestWithCustomDimensionsStruct will test the method WithCustomDimensionsStruct()
+func TestWithCustomDimensionsStruct(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid struct", func(t *testing.T) {
+		options := new(conversionOptions)
+		WithCustomDimensionsStruct(testDimensions{OrderID: "A-100", Total: 19.99})(options)
+		assert.NoError(t, options.dimensionsErr)
+		assert.Equal(t, `{"order_id":"A-100","total":19.99}`, options.customDimensions)
+	})
+
+	t.Run("pointer to struct", func(t *testing.T) {
+		options := new(conversionOptions)
+		WithCustomDimensionsStruct(&testDimensions{OrderID: "A-100", Coupon: "SAVE10"})(options)
+		assert.NoError(t, options.dimensionsErr)
+		assert.Equal(t, `{"coupon":"SAVE10","order_id":"A-100","total":0}`, options.customDimensions)
+	})
+
+	t.Run("nil pointer", func(t *testing.T) {
+		var dimensions *testDimensions
+		options := new(conversionOptions)
+		WithCustomDimensionsStruct(dimensions)(options)
+		assert.NoError(t, options.dimensionsErr)
+		assert.Equal(t, "", options.customDimensions)
+	})
+
+	t.Run("not an object", func(t *testing.T) {
+		for _, v := range []any{"order", 42, []string{"a", "b"}} {
+			options := new(conversionOptions)
+			WithCustomDimensionsStruct(v)(options)
+			assert.ErrorIs(t, options.dimensionsErr, ErrValidation)
+			assert.Equal(t, "", options.customDimensions)
+		}
+	})
+}
+
+// TestConversion_DecodeCustomDimensions will test the method DecodeCustomDimensions()
+func TestConversion_DecodeCustomDimensions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("decode into struct", func(t *testing.T) {
+		conversion := &Conversion{CustomDimensions: `{"order_id":"A-100","total":19.99}`}
+		var dimensions testDimensions
+		assert.NoError(t, conversion.DecodeCustomDimensions(&dimensions))
+		assert.Equal(t, testDimensions{OrderID: "A-100", Total: 19.99}, dimensions)
+	})
+
+	t.Run("decode into map", func(t *testing.T) {
+		conversion := &Conversion{CustomDimensions: `{"order_id":"A-100","vip":true}`}
+		var dimensions map[string]any
+		assert.NoError(t, conversion.DecodeCustomDimensions(&dimensions))
+		assert.Equal(t, map[string]any{"order_id": "A-100", "vip": true}, dimensions)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		conversion := &Conversion{}
+		dimensions := testDimensions{OrderID: "unchanged"}
+		assert.NoError(t, conversion.DecodeCustomDimensions(&dimensions))
+		assert.Equal(t, "unchanged", dimensions.OrderID)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		conversion := &Conversion{CustomDimensions: "order=A-100"}
+		var dimensions map[string]any
+		assert.Error(t, conversion.DecodeCustomDimensions(&dimensions))
+	})
+}
+
+// TestClient_CreateConversion_CustomDimensions will test custom dimensions with CreateConversion()
+func TestClient_CreateConversion_CustomDimensions(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("sends encoded dimensions", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelConversion)
+		var sent string
+		httpmock.RegisterResponder(http.MethodPost, endpoint, func(req *http.Request) (*http.Response, error) {
+			var payload map[string]string
+			if decodeErr := json.NewDecoder(req.Body).Decode(&payload); decodeErr != nil {
+				return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+			}
+			sent = payload[fieldCustomDimensions]
+			conversion := newTestConversion()
+			conversion.CustomDimensions = sent
+			return httpmock.NewJsonResponse(http.StatusCreated, conversion)
+		})
+
+		var conversion *Conversion
+		conversion, _, err = client.CreateConversionWithContext(
+			context.Background(),
+			WithGoalID(testGoalID),
+			WithTncpwSession(testTncpwSession),
+			WithCustomDimensionsStruct(testDimensions{OrderID: "A-100", Total: 19.99}),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"order_id":"A-100","total":19.99}`, sent)
+
+		var dimensions testDimensions
+		assert.NoError(t, conversion.DecodeCustomDimensions(&dimensions))
+		assert.Equal(t, "A-100", dimensions.OrderID)
+	})
+
+	t.Run("invalid dimensions are not sent", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+		httpmock.ZeroCallCounters()
+
+		var conversion *Conversion
+		conversion, _, err = client.CreateConversion(
+			WithGoalID(testGoalID),
+			WithTncpwSession(testTncpwSession),
+			WithCustomDimensionsMap(map[string]any{"order id": "A-100"}),
+		)
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Nil(t, conversion)
+		assert.Equal(t, 0, httpmock.GetTotalCallCount())
+	})
+}
@@ -6,11 +6,37 @@ import (
 
 const (
 	// Package configuration defaults
-	apiVersion         string = "v1"
-	defaultHTTPTimeout        = 10 * time.Second          // Default timeout for all GET requests in seconds
-	defaultRetryCount  int    = 2                         // Default retry count for HTTP requests
-	defaultUserAgent          = "go-tonicpow: " + version // Default user agent
-	version            string = "v0.8.0"                  // go-tonicpow version
+	apiTimeFormat                        = "2006-01-02 15:04:05" // Format of times from the API (UTC)
+	apiVersion                    string = "v1"
+	conversionCancelMargin               = time.Minute               // Conversions can be canceled until one minute before the payout
+	defaultBatchConcurrency              = 4                         // Default number of conversions in flight (CreateConversions)
+	defaultFeedMaxBackoff                = 5 * time.Minute           // Default maximum backoff after failed polls (FeedWatcher)
+	defaultFeedMinBackoff                = time.Second               // Default backoff after a failed poll (FeedWatcher)
+	defaultFeedPollInterval              = 5 * time.Minute           // Default delay between polls (FeedWatcher)
+	defaultHTTPTimeout                   = 10 * time.Second          // Default timeout for all GET requests in seconds
+	defaultOutboxCompactThreshold        = 64                        // Extra log records before the outbox file is compacted
+	defaultOutboxMaxAttempts             = 10                        // Default attempts before a conversion is dead-lettered
+	defaultOutboxMaxBackoff              = 15 * time.Minute          // Default maximum delay between outbox attempts
+	defaultOutboxMinBackoff              = 5 * time.Second           // Default delay after a failed outbox attempt
+	defaultOutboxPollInterval            = time.Second               // Default delay between checks for due conversions
+	defaultPollInterval                  = 10 * time.Second          // Default delay between polls (ConversionPoller)
+	defaultRateLimitPause                = time.Second               // Default pause after a 429 (without Retry-After)
+	defaultRateStaleTTL                  = 10 * time.Minute          // Default time an expired rate is served while refreshing (RateCache)
+	defaultRateTTL                       = time.Minute               // Default time a rate is fresh (RateCache)
+	defaultResultsPerPage                = 20                        // Default results per page (Iterator)
+	defaultRetryBaseDelay                = 200 * time.Millisecond    // Default delay before the first retry (RetryPolicy)
+	defaultRetryCount             int    = 2                         // Default retry count for HTTP requests
+	defaultRetryJitter                   = 0.2                       // Default jitter for retry delays (RetryPolicy)
+	defaultRetryMaxDelay                 = 5 * time.Second           // Default maximum delay between retries (RetryPolicy)
+	defaultSessionMaxAge                 = 30 * 24 * time.Hour       // Default lifetime of the session cookie (SessionMiddleware)
+	defaultTrackerWarning                = 5 * time.Minute           // Default warning before the cancel window closes (ConversionTracker)
+	defaultUserAgent                     = "go-tonicpow: " + version // Default user agent
+	defaultWaitInterval                  = 2 * time.Second           // Default delay before the first poll (WaitForConversion)
+	defaultWaitMaxInterval               = 30 * time.Second          // Default maximum delay between polls (WaitForConversion)
+	maxCustomDimensionsKeyLength         = 64                        // Maximum length of a custom dimension key
+	maxCustomDimensionsSize              = 4096                      // Maximum size of the custom dimensions (encoded JSON bytes)
+	maxSessionLength                     = 128                       // Maximum length of a tncpw_session
+	version                       string = "v0.8.0"                  // go-tonicpow version
 
 	// Field key names for various model requests
 	fieldAdvertiserProfileID = "advertiser_profile_id"
@@ -44,6 +70,11 @@ const (
 	fieldUserID             = "user_id"
 	fieldVisitorSessionGUID = "tncpw_session"
 
+	// Header names for requests & responses
+	headerIdempotencyKey     = "Idempotency-Key"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+
 	// Model names (used for Request endpoints)
 	modelAdvertiser string = "advertisers"
 	modelApp        string = "apps"
@@ -97,10 +128,51 @@ const (
 
 	// FeedTypeRSS is for using the feed type: RSS
 	FeedTypeRSS FeedType = "rss"
+
+	// ConversionStatusPending is for a conversion waiting to be processed (IE: delayed)
+	ConversionStatusPending ConversionStatus = "pending"
+
+	// ConversionStatusProcessing is for a conversion that is being paid out
+	ConversionStatusProcessing ConversionStatus = "processing"
+
+	// ConversionStatusPaid is for a conversion that has been paid (see TxID)
+	ConversionStatusPaid ConversionStatus = "paid"
+
+	// ConversionStatusFailed is for a conversion that failed (see StatusData)
+	ConversionStatusFailed ConversionStatus = "failed"
+
+	// ConversionStatusCancelled is for a conversion that was canceled
+	ConversionStatusCancelled ConversionStatus = "cancelled"
+
+	// CurrencyBSV is for using the currency: BSV (see: Satoshis)
+	CurrencyBSV = "bsv"
+
+	// CurrencyEUR is for using the currency: Euro
+	CurrencyEUR = "eur"
+
+	// CurrencyGBP is for using the currency: British Pound
+	CurrencyGBP = "gbp"
+
+	// CurrencyJPY is for using the currency: Japanese Yen
+	CurrencyJPY = "jpy"
+
+	// CurrencyUSD is for using the currency: US Dollar
+	CurrencyUSD = "usd"
 )
 
 var (
 
+	// currencyFormats are the display formats of the known currencies (see: Money.Format)
+	currencyFormats = map[string]currencyFormat{
+		"aud":       {decimals: 2, symbol: "A$"},
+		"cad":       {decimals: 2, symbol: "CA$"},
+		CurrencyBSV: {decimals: 8},
+		CurrencyEUR: {decimals: 2, symbol: "€"},
+		CurrencyGBP: {decimals: 2, symbol: "£"},
+		CurrencyJPY: {decimals: 0, symbol: "¥"},
+		CurrencyUSD: {decimals: 2, symbol: "$"},
+	}
+
 	// appSortFields is used for allowing specific fields for sorting
 	appSortFields = []string{
 		SortByFieldCreatedAt,
@@ -115,11 +187,25 @@ var (
 		SortByFieldPaidClicks,
 		SortByFieldPayPerClick,
 	}
+
+	// sortOrders is used for allowing specific sort orders
+	sortOrders = []string{
+		SortOrderAsc,
+		SortOrderDesc,
+	}
 )
 
 // FeedType is used for the campaign feeds (rss, atom, json)
 type FeedType string
 
+// ConversionStatus is the status of a conversion (pending, processing, paid, failed, cancelled)
+type ConversionStatus string
+
+// IsTerminal will return true if the status will not change again (paid, failed or cancelled)
+func (s ConversionStatus) IsTerminal() bool {
+	return s == ConversionStatusPaid || s == ConversionStatusFailed || s == ConversionStatusCancelled
+}
+
 // Environment is used for changing the Environment for running client requests
 type Environment struct {
 	alias  string
@@ -0,0 +1,107 @@
+package tonicpow
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors that can be matched using errors.Is()
+var (
+	// ErrNotFound is returned when the requested resource was not found (404)
+	ErrNotFound = errors.New("resource not found")
+
+	// ErrRateLimited is returned when the API key has exceeded the rate limit (429)
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrServerError is returned when the API failed to process the request (5xx)
+	ErrServerError = errors.New("api server error")
+
+	// ErrUnauthorized is returned when the API key is missing, invalid or lacks permission (401 / 403)
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrValidation is returned when the request is invalid (400 / 422) or fails client-side validation
+	ErrValidation = errors.New("validation failed")
+)
+
+// Error will return the API error message (implements the error interface)
+func (e *Error) Error() string {
+	if len(e.Message) > 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// Unwrap will return the sentinel error for the status code (if any)
+//
+// This allows: errors.Is(err, ErrNotFound)
+func (e *Error) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusUnauthorized, e.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusBadRequest, e.StatusCode == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServerError
+	}
+	return nil
+}
+
+// ValidationError is returned when a request fails client-side validation (before it is sent)
+type ValidationError struct {
+	Field   string // Field name that failed validation (alternatives are joined with "or")
+	Message string // Description of the failure
+}
+
+// Error will return the validation message (implements the error interface)
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Unwrap will return ErrValidation
+//
+// This allows: errors.Is(err, ErrValidation)
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// newMissingAttributeError will return a ValidationError for missing required attribute(s)
+func newMissingAttributeError(fields ...string) error {
+	field := strings.Join(fields, " or ")
+	if len(fields) > 1 {
+		return &ValidationError{Field: field, Message: "missing required attribute(s): " + field}
+	}
+	return &ValidationError{Field: field, Message: "missing required attribute: " + field}
+}
+
+// newInvalidSortByError will return a ValidationError for an unknown sort by field
+func newInvalidSortByError(sortBy string) error {
+	return &ValidationError{Field: fieldSortBy, Message: fmt.Sprintf("sort by %s is not valid", sortBy)}
+}
+
+// newInvalidFeedTypeError will return a ValidationError for an unknown feed type
+func newInvalidFeedTypeError(feedType FeedType) error {
+	return &ValidationError{Field: fieldFeedType, Message: fmt.Sprintf("feed type %s is not valid", feedType)}
+}
+
+// newInvalidSortOrderError will return a ValidationError for an unknown sort order
+func newInvalidSortOrderError(sortOrder string) error {
+	return &ValidationError{Field: fieldSortOrder, Message: fmt.Sprintf("sort order %s is not valid", sortOrder)}
+}
+
+// newInvalidCustomDimensionsError will return a ValidationError for custom dimensions that cannot be sent
+func newInvalidCustomDimensionsError(message string) error {
+	return &ValidationError{Field: fieldCustomDimensions, Message: "custom dimensions are not valid: " + message}
+}
+
+// isPermanentError will return true if sending the same request again will not help
+func isPermanentError(err error) bool {
+	return errors.Is(err, ErrValidation) ||
+		errors.Is(err, ErrUnauthorized) ||
+		errors.Is(err, ErrNotFound)
+}
@@ -0,0 +1,154 @@
+package tonicpow
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestError_Error will test the method Error()
+func TestError_Error(t *testing.T) {
+	t.Parallel()
+
+	t.Run("api message", func(t *testing.T) {
+		e := &Error{Message: "some error message", StatusCode: http.StatusBadRequest}
+		assert.Equal(t, "some error message", e.Error())
+	})
+
+	t.Run("missing message", func(t *testing.T) {
+		e := &Error{StatusCode: http.StatusBadGateway}
+		assert.Equal(t, "unexpected status code: 502", e.Error())
+	})
+}
+
+// TestError_Unwrap will test the method Unwrap()
+func TestError_Unwrap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		statusCode int
+		expected   error
+	}{
+		{http.StatusBadRequest, ErrValidation},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusUnprocessableEntity, ErrValidation},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusInternalServerError, ErrServerError},
+		{http.StatusServiceUnavailable, ErrServerError},
+		{http.StatusConflict, nil},
+		{0, nil},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("status code %d", test.statusCode), func(t *testing.T) {
+			e := &Error{StatusCode: test.statusCode}
+			assert.Equal(t, test.expected, e.Unwrap())
+			if test.expected != nil {
+				assert.ErrorIs(t, e, test.expected)
+			}
+		})
+	}
+}
+
+// TestValidationError will test the ValidationError type
+func TestValidationError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing attribute", func(t *testing.T) {
+		err := newMissingAttributeError(fieldID)
+		assert.Equal(t, "missing required attribute: id", err.Error())
+		assert.ErrorIs(t, err, ErrValidation)
+
+		var validationErr *ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, fieldID, validationErr.Field)
+	})
+
+	t.Run("missing attributes", func(t *testing.T) {
+		err := newMissingAttributeError(fieldID, fieldName)
+		assert.Equal(t, "missing required attribute(s): id or name", err.Error())
+
+		var validationErr *ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, "id or name", validationErr.Field)
+	})
+
+	t.Run("invalid sort by", func(t *testing.T) {
+		err := newInvalidSortByError("bad_field")
+		assert.Equal(t, "sort by bad_field is not valid", err.Error())
+		assert.ErrorIs(t, err, ErrValidation)
+
+		var validationErr *ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, fieldSortBy, validationErr.Field)
+	})
+}
+
+// TestClient_Request_Errors will test the errors returned from Request()
+func TestClient_Request_Errors(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s/details/%d", EnvironmentDevelopment.apiURL, modelGoal, testGoalID)
+
+	t.Run("api error (not found)", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		apiError := &Error{
+			Code:        404,
+			Message:     "goal not found",
+			Method:      http.MethodGet,
+			RequestGUID: "7f3d97a8fd67ff57861904df6118dcc8",
+			StatusCode:  http.StatusNotFound,
+			URL:         endpoint,
+		}
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusNotFound, apiError)
+		assert.NoError(t, err)
+
+		var goal *Goal
+		var response *StandardResponse
+		goal, response, err = client.GetGoal(testGoalID)
+		assert.Nil(t, goal)
+		assert.NotNil(t, response)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NotErrorIs(t, err, ErrValidation)
+
+		var responseErr *Error
+		assert.True(t, errors.As(err, &responseErr))
+		assert.Equal(t, apiError.RequestGUID, responseErr.RequestGUID)
+		assert.Equal(t, response.Error, responseErr)
+	})
+
+	t.Run("non-json error body", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseFeed(endpoint, http.StatusServiceUnavailable, "<html>unavailable</html>")
+
+		var response *StandardResponse
+		_, response, err = client.GetGoal(testGoalID)
+		assert.NotNil(t, response)
+		assert.ErrorIs(t, err, ErrServerError)
+		assert.Equal(t, http.StatusServiceUnavailable, response.Error.StatusCode)
+	})
+
+	t.Run("client-side validation", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		_, _, err = client.GetGoal(0)
+		assert.ErrorIs(t, err, ErrValidation)
+
+		var validationErr *ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, fieldID, validationErr.Field)
+	})
+}
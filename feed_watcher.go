@@ -0,0 +1,292 @@
+package tonicpow
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FeedEventType is the type of change found by the FeedWatcher
+type FeedEventType string
+
+const (
+	// FeedEventAdded is for a feed item that has not been seen before
+	FeedEventAdded FeedEventType = "added"
+
+	// FeedEventUpdated is for a seen feed item that has changed
+	FeedEventUpdated FeedEventType = "updated"
+
+	// FeedEventRemoved is for a seen feed item that is no longer in the feed
+	FeedEventRemoved FeedEventType = "removed"
+)
+
+// FeedEvent is a change in the campaigns feed
+type FeedEvent struct {
+	Item *FeedItem     // The feed item (the last seen item for FeedEventRemoved)
+	Key  string        // Key of the item (campaign slug, id or link)
+	Type FeedEventType // Added, updated or removed
+}
+
+// FeedStore stores the feed items seen by the FeedWatcher (by key)
+//
+// Implement this interface to persist seen items between restarts
+type FeedStore interface {
+	Load(ctx context.Context) (map[string]*FeedItem, error)
+	Save(ctx context.Context, items map[string]*FeedItem) error
+}
+
+// MemoryFeedStore is an in-memory FeedStore (the default store)
+type MemoryFeedStore struct {
+	items map[string]*FeedItem
+	mu    sync.Mutex
+}
+
+// NewMemoryFeedStore will return a new in-memory FeedStore
+func NewMemoryFeedStore() *MemoryFeedStore {
+	return &MemoryFeedStore{items: make(map[string]*FeedItem)}
+}
+
+// Load will return a copy of the seen items
+func (m *MemoryFeedStore) Load(_ context.Context) (map[string]*FeedItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := make(map[string]*FeedItem, len(m.items))
+	for key, item := range m.items {
+		items[key] = item
+	}
+	return items, nil
+}
+
+// Save will replace the seen items
+func (m *MemoryFeedStore) Save(_ context.Context, items map[string]*FeedItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items = make(map[string]*FeedItem, len(items))
+	for key, item := range items {
+		m.items[key] = item
+	}
+	return nil
+}
+
+// FeedWatcherOps allow functional options to be supplied
+// that overwrite default feed watcher options.
+type FeedWatcherOps func(o *feedWatcherOptions)
+
+// feedWatcherOptions holds all the configuration for the feed watcher
+type feedWatcherOptions struct {
+	errorHandler func(err error) // Called when polling fails (before backing off)
+	feedType     FeedType        // Feed to poll
+	interval     time.Duration   // Delay between polls
+	maxBackoff   time.Duration   // Maximum delay after consecutive errors
+	minBackoff   time.Duration   // Delay after the first error
+	store        FeedStore       // Seen items
+}
+
+// WithFeedType will set the feed type to poll
+// Default is FeedTypeJSON.
+func WithFeedType(feedType FeedType) FeedWatcherOps {
+	return func(o *feedWatcherOptions) {
+		o.feedType = feedType
+	}
+}
+
+// WithFeedPollInterval will set the delay between polls (values <= 0 are ignored)
+// Default is 5 minutes.
+func WithFeedPollInterval(interval time.Duration) FeedWatcherOps {
+	return func(o *feedWatcherOptions) {
+		if interval > 0 {
+			o.interval = interval
+		}
+	}
+}
+
+// WithFeedStore will set the store for seen items
+// Default is an in-memory store (NewMemoryFeedStore).
+func WithFeedStore(store FeedStore) FeedWatcherOps {
+	return func(o *feedWatcherOptions) {
+		o.store = store
+	}
+}
+
+// WithFeedBackoff will set the delay after a failed poll (doubling on each consecutive failure)
+// Values <= 0 are ignored and the maximum is raised to the minimum if it is lower.
+// Default is 1 second up to 5 minutes.
+func WithFeedBackoff(minBackoff, maxBackoff time.Duration) FeedWatcherOps {
+	return func(o *feedWatcherOptions) {
+		if minBackoff > 0 {
+			o.minBackoff = minBackoff
+		}
+		if maxBackoff > 0 {
+			o.maxBackoff = maxBackoff
+		}
+		if o.maxBackoff < o.minBackoff {
+			o.maxBackoff = o.minBackoff
+		}
+	}
+}
+
+// WithFeedErrorHandler will set a function that is called when polling fails
+func WithFeedErrorHandler(handler func(err error)) FeedWatcherOps {
+	return func(o *feedWatcherOptions) {
+		o.errorHandler = handler
+	}
+}
+
+// FeedWatcher polls the campaigns feed and emits events for new, changed and removed campaigns
+type FeedWatcher struct {
+	client  CampaignService
+	options *feedWatcherOptions
+}
+
+// NewFeedWatcher will return a new FeedWatcher using the client
+func NewFeedWatcher(client CampaignService, opts ...FeedWatcherOps) *FeedWatcher {
+	options := &feedWatcherOptions{
+		feedType:   FeedTypeJSON,
+		interval:   defaultFeedPollInterval,
+		maxBackoff: defaultFeedMaxBackoff,
+		minBackoff: defaultFeedMinBackoff,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.store == nil {
+		options.store = NewMemoryFeedStore()
+	}
+	return &FeedWatcher{client: client, options: options}
+}
+
+// Watch will poll the feed until the context is canceled, sending the events on the returned channel
+//
+// Failed polls are retried with backoff (see WithFeedErrorHandler), and the
+// channel is closed once the context is canceled. Seen items are saved after
+// the events of a poll have been received (items can be sent again after a restart)
+func (w *FeedWatcher) Watch(ctx context.Context) <-chan FeedEvent {
+	events := make(chan FeedEvent)
+	go func() {
+		defer close(events)
+		var backoff time.Duration
+		for {
+			delay := w.options.interval
+			if err := w.watchOnce(ctx, events); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if w.options.errorHandler != nil {
+					w.options.errorHandler(err)
+				}
+				backoff = w.nextBackoff(backoff)
+				delay = backoff
+			} else {
+				backoff = 0
+			}
+			if sleepContext(ctx, delay) != nil {
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// Poll will poll the feed once, save the seen items and return the events
+func (w *FeedWatcher) Poll(ctx context.Context) ([]FeedEvent, error) {
+	events, current, err := w.diff(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = w.options.store.Save(ctx, current); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// watchOnce will poll the feed, send the events and save the seen items
+func (w *FeedWatcher) watchOnce(ctx context.Context, events chan<- FeedEvent) error {
+	changes, current, err := w.diff(ctx)
+	if err != nil {
+		return err
+	}
+	for _, event := range changes {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return w.options.store.Save(ctx, current)
+}
+
+// diff will fetch the feed and compare it to the seen items
+func (w *FeedWatcher) diff(ctx context.Context) (events []FeedEvent, current map[string]*FeedItem, err error) {
+	var items []*FeedItem
+	if items, _, err = w.client.CampaignsFeedItemsWithContext(ctx, w.options.feedType); err != nil {
+		return
+	}
+	var seen map[string]*FeedItem
+	if seen, err = w.options.store.Load(ctx); err != nil {
+		return
+	}
+
+	// Added & updated (in feed order)
+	current = make(map[string]*FeedItem, len(items))
+	for _, item := range items {
+		key := feedItemKey(item)
+		if _, ok := current[key]; ok || len(key) == 0 {
+			continue
+		}
+		current[key] = item
+		if previous, ok := seen[key]; !ok {
+			events = append(events, FeedEvent{Item: item, Key: key, Type: FeedEventAdded})
+		} else if feedItemChanged(previous, item) {
+			events = append(events, FeedEvent{Item: item, Key: key, Type: FeedEventUpdated})
+		}
+	}
+
+	// Removed (sorted by key)
+	removed := make([]string, 0)
+	for key := range seen {
+		if _, ok := current[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	for _, key := range removed {
+		events = append(events, FeedEvent{Item: seen[key], Key: key, Type: FeedEventRemoved})
+	}
+	return
+}
+
+// nextBackoff will return the delay after another failed poll
+func (w *FeedWatcher) nextBackoff(backoff time.Duration) time.Duration {
+	if backoff <= 0 {
+		return w.options.minBackoff
+	}
+	if backoff *= 2; backoff > w.options.maxBackoff {
+		return w.options.maxBackoff
+	}
+	return backoff
+}
+
+// feedItemKey will return the key used to track the item
+func feedItemKey(item *FeedItem) string {
+	switch {
+	case len(item.CampaignSlug) > 0:
+		return item.CampaignSlug
+	case len(item.ID) > 0:
+		return item.ID
+	default:
+		return item.Link
+	}
+}
+
+// feedItemChanged will return true if the item has changed (times are compared as instants)
+func feedItemChanged(previous, item *FeedItem) bool {
+	return previous.Title != item.Title ||
+		previous.Description != item.Description ||
+		previous.ImageURL != item.ImageURL ||
+		previous.Link != item.Link ||
+		previous.ID != item.ID ||
+		previous.CampaignID != item.CampaignID ||
+		!previous.Published.Equal(item.Published) ||
+		!previous.Updated.Equal(item.Updated)
+}
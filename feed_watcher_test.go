@@ -0,0 +1,249 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testFeedClient is a CampaignService returning feed items from a list of polls
+type testFeedClient struct {
+	CampaignService
+	mu    sync.Mutex
+	polls []func() ([]*FeedItem, error)
+}
+
+// CampaignsFeedItemsWithContext will return the next poll (the last poll is repeated)
+func (c *testFeedClient) CampaignsFeedItemsWithContext(_ context.Context,
+	_ FeedType) ([]*FeedItem, *StandardResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	poll := c.polls[0]
+	if len(c.polls) > 1 {
+		c.polls = c.polls[1:]
+	}
+	items, err := poll()
+	return items, nil, err
+}
+
+// newTestFeedItems will return feed items for the given slugs
+func newTestFeedItems(slugs ...string) func() ([]*FeedItem, error) {
+	return func() ([]*FeedItem, error) {
+		items := make([]*FeedItem, 0, len(slugs))
+		for _, slug := range slugs {
+			items = append(items, &FeedItem{
+				CampaignSlug: slug,
+				Link:         "https://tonicpow.com/campaign/" + slug,
+				Title:        slug,
+			})
+		}
+		return items, nil
+	}
+}
+
+// TestFeedWatcher_Poll will test the method Poll()
+func TestFeedWatcher_Poll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("added, updated and removed", func(t *testing.T) {
+		client := &testFeedClient{polls: []func() ([]*FeedItem, error){
+			newTestFeedItems("a", "b"),
+			func() ([]*FeedItem, error) {
+				items, _ := newTestFeedItems("a", "b", "c")()
+				items[1].Title = "b changed"
+				return items, nil
+			},
+			newTestFeedItems("c"),
+		}}
+		watcher := NewFeedWatcher(client)
+
+		events, err := watcher.Poll(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []FeedEventType{FeedEventAdded, FeedEventAdded}, feedEventTypes(events))
+		assert.Equal(t, "a", events[0].Key)
+
+		events, err = watcher.Poll(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []FeedEventType{FeedEventUpdated, FeedEventAdded}, feedEventTypes(events))
+		assert.Equal(t, "b changed", events[0].Item.Title)
+		assert.Equal(t, "c", events[1].Key)
+
+		events, err = watcher.Poll(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []FeedEventType{FeedEventRemoved, FeedEventRemoved}, feedEventTypes(events))
+		assert.Equal(t, "a", events[0].Key)
+		assert.Equal(t, "b changed", events[1].Item.Title)
+
+		events, err = watcher.Poll(context.Background())
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("times are compared as instants", func(t *testing.T) {
+		published := time.Date(2021, 6, 4, 17, 23, 38, 0, time.UTC)
+		store := NewMemoryFeedStore()
+		assert.NoError(t, store.Save(context.Background(), map[string]*FeedItem{
+			"a": {CampaignSlug: "a", Published: published.In(time.FixedZone("EST", -5*3600))},
+		}))
+
+		client := &testFeedClient{polls: []func() ([]*FeedItem, error){
+			func() ([]*FeedItem, error) {
+				return []*FeedItem{{CampaignSlug: "a", Published: published}}, nil
+			},
+		}}
+		events, err := NewFeedWatcher(client, WithFeedStore(store)).Poll(context.Background())
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("error does not change the store", func(t *testing.T) {
+		store := NewMemoryFeedStore()
+		client := &testFeedClient{polls: []func() ([]*FeedItem, error){
+			newTestFeedItems("a"),
+			func() ([]*FeedItem, error) { return nil, errors.New("feed failed") },
+			newTestFeedItems("a"),
+		}}
+		watcher := NewFeedWatcher(client, WithFeedStore(store))
+
+		_, err := watcher.Poll(context.Background())
+		assert.NoError(t, err)
+
+		var events []FeedEvent
+		events, err = watcher.Poll(context.Background())
+		assert.EqualError(t, err, "feed failed")
+		assert.Nil(t, events)
+
+		events, err = watcher.Poll(context.Background())
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+}
+
+// TestFeedWatcher_Watch will test the method Watch()
+func TestFeedWatcher_Watch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("emits events and backs off on errors", func(t *testing.T) {
+		var mu sync.Mutex
+		var errs []error
+		client := &testFeedClient{polls: []func() ([]*FeedItem, error){
+			newTestFeedItems("a"),
+			func() ([]*FeedItem, error) { return nil, errors.New("feed failed") },
+			func() ([]*FeedItem, error) { return nil, errors.New("feed failed") },
+			newTestFeedItems("a", "b"),
+		}}
+		watcher := NewFeedWatcher(
+			client,
+			WithFeedPollInterval(time.Millisecond),
+			WithFeedBackoff(time.Millisecond, 2*time.Millisecond),
+			WithFeedErrorHandler(func(err error) {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}),
+		)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		events := watcher.Watch(ctx)
+
+		event := <-events
+		assert.Equal(t, FeedEventAdded, event.Type)
+		assert.Equal(t, "a", event.Key)
+
+		event = <-events
+		assert.Equal(t, FeedEventAdded, event.Type)
+		assert.Equal(t, "b", event.Key)
+
+		// Wait for the watcher to stop
+		cancel()
+		for range events {
+			continue
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Len(t, errs, 2)
+	})
+
+	t.Run("stops on context cancellation", func(t *testing.T) {
+		client := &testFeedClient{polls: []func() ([]*FeedItem, error){newTestFeedItems("a", "b")}}
+		ctx, cancel := context.WithCancel(context.Background())
+		events := NewFeedWatcher(client, WithFeedPollInterval(time.Hour)).Watch(ctx)
+
+		<-events
+		cancel()
+
+		select {
+		case _, ok := <-events:
+			if ok {
+				_, ok = <-events
+			}
+			assert.False(t, ok)
+		case <-time.After(5 * time.Second):
+			t.Fatal("watcher did not stop")
+		}
+	})
+
+	t.Run("next backoff", func(t *testing.T) {
+		watcher := NewFeedWatcher(nil, WithFeedBackoff(time.Second, 3*time.Second))
+		assert.Equal(t, time.Second, watcher.nextBackoff(0))
+		assert.Equal(t, 2*time.Second, watcher.nextBackoff(time.Second))
+		assert.Equal(t, 3*time.Second, watcher.nextBackoff(2*time.Second))
+	})
+}
+
+// TestNewFeedWatcher will test the method NewFeedWatcher()
+func TestNewFeedWatcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid durations are ignored", func(t *testing.T) {
+		w := NewFeedWatcher(nil, WithFeedPollInterval(0), WithFeedBackoff(-time.Second, 0))
+		assert.Equal(t, defaultFeedPollInterval, w.options.interval)
+		assert.Equal(t, defaultFeedMinBackoff, w.options.minBackoff)
+		assert.Equal(t, defaultFeedMaxBackoff, w.options.maxBackoff)
+	})
+
+	t.Run("maximum backoff is raised to the minimum", func(t *testing.T) {
+		w := NewFeedWatcher(nil, WithFeedBackoff(time.Minute, time.Second))
+		assert.Equal(t, time.Minute, w.options.minBackoff)
+		assert.Equal(t, time.Minute, w.options.maxBackoff)
+	})
+}
+
+// TestFeedWatcher_Client will test the FeedWatcher using the client
+func TestFeedWatcher_Client(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	client, err := newTestClient()
+	assert.NoError(t, err)
+
+	endpoint := fmt.Sprintf(
+		"%s/%s/feed/?%s=%s", EnvironmentDevelopment.apiURL,
+		modelCampaign, fieldFeedType, FeedTypeAtom,
+	)
+	mockResponseFeed(endpoint, http.StatusOK, newTestCampaignFeedAtom())
+
+	var events []FeedEvent
+	events, err = NewFeedWatcher(client, WithFeedType(FeedTypeAtom)).Poll(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, FeedEventAdded, events[0].Type)
+		assert.Equal(t, "tonicpow", events[0].Key)
+	}
+}
+
+// feedEventTypes will return the types of the events
+func feedEventTypes(events []FeedEvent) []FeedEventType {
+	types := make([]FeedEventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
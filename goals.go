@@ -1,6 +1,7 @@
 package tonicpow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -15,17 +16,23 @@ func (g *Goal) permitFields() {
 //
 // For more information: https://docs.tonicpow.com/#29a93e9b-9726-474c-b25e-92586200a803
 func (c *Client) CreateGoal(goal *Goal) (*StandardResponse, error) {
+	return c.CreateGoalWithContext(context.Background(), goal)
+}
+
+// CreateGoalWithContext is the same as CreateGoal, but uses the given context for the request
+func (c *Client) CreateGoalWithContext(ctx context.Context, goal *Goal) (*StandardResponse, error) {
 
 	// Basic requirements
 	if goal.CampaignID == 0 {
-		return nil, fmt.Errorf(fmt.Sprintf("missing required attribute: %s", fieldCampaignID))
+		return nil, newMissingAttributeError(fieldCampaignID)
 	} else if len(goal.Name) == 0 {
-		return nil, fmt.Errorf(fmt.Sprintf("missing required attribute: %s", fieldName))
+		return nil, newMissingAttributeError(fieldName)
 	}
 
 	// Fire the Request
-	response, err := c.Request(
-		http.MethodPost,
+	ctx = withOperation(ctx, Operation{Name: "CreateGoal", CampaignID: goal.CampaignID})
+	response, err := c.RequestWithContext(
+		ctx, http.MethodPost,
 		"/"+modelGoal,
 		goal, http.StatusCreated,
 	)
@@ -41,16 +48,22 @@ func (c *Client) CreateGoal(goal *Goal) (*StandardResponse, error) {
 //
 // For more information: https://docs.tonicpow.com/#48d7bbc8-5d7b-4078-87b7-25f545c3deaf
 func (c *Client) GetGoal(goalID uint64) (goal *Goal, response *StandardResponse, err error) {
+	return c.GetGoalWithContext(context.Background(), goalID)
+}
+
+// GetGoalWithContext is the same as GetGoal, but uses the given context for the request
+func (c *Client) GetGoalWithContext(ctx context.Context, goalID uint64) (goal *Goal, response *StandardResponse, err error) {
 
 	// Must have an ID
 	if goalID == 0 {
-		err = fmt.Errorf("missing required attribute: %s", fieldID)
+		err = newMissingAttributeError(fieldID)
 		return
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
+	ctx = withOperation(ctx, Operation{Name: "GetGoal", GoalID: goalID})
+	if response, err = c.RequestWithContext(
+		ctx, http.MethodGet,
 		fmt.Sprintf("/%s/details/%d", modelGoal, goalID),
 		nil, http.StatusOK,
 	); err != nil {
@@ -65,18 +78,24 @@ func (c *Client) GetGoal(goalID uint64) (goal *Goal, response *StandardResponse,
 //
 // For more information: https://docs.tonicpow.com/#395f5b7d-6a5d-49c8-b1ae-abf7f90b42a2
 func (c *Client) UpdateGoal(goal *Goal) (*StandardResponse, error) {
+	return c.UpdateGoalWithContext(context.Background(), goal)
+}
+
+// UpdateGoalWithContext is the same as UpdateGoal, but uses the given context for the request
+func (c *Client) UpdateGoalWithContext(ctx context.Context, goal *Goal) (*StandardResponse, error) {
 
 	// Basic requirements
 	if goal.ID == 0 {
-		return nil, fmt.Errorf("missing required attribute: %s", fieldID)
+		return nil, newMissingAttributeError(fieldID)
 	}
 
 	// Permit fields
 	goal.permitFields()
 
 	// Fire the Request
-	response, err := c.Request(
-		http.MethodPut,
+	ctx = withOperation(ctx, Operation{Name: "UpdateGoal", GoalID: goal.ID})
+	response, err := c.RequestWithContext(
+		ctx, http.MethodPut,
 		"/"+modelGoal,
 		goal, http.StatusOK,
 	)
@@ -91,15 +110,21 @@ func (c *Client) UpdateGoal(goal *Goal) (*StandardResponse, error) {
 //
 // For more information: https://docs.tonicpow.com/#38605b65-72c9-4fc8-87a7-bc644bc89a96
 func (c *Client) DeleteGoal(goalID uint64) (bool, *StandardResponse, error) {
+	return c.DeleteGoalWithContext(context.Background(), goalID)
+}
+
+// DeleteGoalWithContext is the same as DeleteGoal, but uses the given context for the request
+func (c *Client) DeleteGoalWithContext(ctx context.Context, goalID uint64) (bool, *StandardResponse, error) {
 
 	// Basic requirements
 	if goalID == 0 {
-		return false, nil, fmt.Errorf("missing required attribute: %s", fieldID)
+		return false, nil, newMissingAttributeError(fieldID)
 	}
 
 	// Fire the Request
-	response, err := c.Request(
-		http.MethodDelete,
+	ctx = withOperation(ctx, Operation{Name: "DeleteGoal", GoalID: goalID})
+	response, err := c.RequestWithContext(
+		ctx, http.MethodDelete,
 		fmt.Sprintf("/%s?%s=%d", modelGoal, fieldID, goalID),
 		nil, http.StatusOK,
 	)
@@ -0,0 +1,58 @@
+package tonicpow
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// idempotencyKeyContextKey is the context key for the idempotency key
+type idempotencyKeyContextKey struct{}
+
+// NewIdempotencyKey will return a new random idempotency key (32 hex characters)
+//
+// Generate the key before the request and persist it (IE: alongside an order ID)
+// so the same key can be reused if the request needs to be sent again.
+//
+// An empty key is returned if the random source fails (no key is sent with the request)
+func NewIdempotencyKey() string {
+	key, _ := newIdempotencyKey()
+	return key
+}
+
+// newIdempotencyKey will return a new random idempotency key, or the error from the random source
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ContextWithIdempotencyKey will return a context that sends the idempotency key on write requests
+//
+// Use with CreateCampaignWithContext(), CreateGoalWithContext(), etc.
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext will return the idempotency key from the context (if set)
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+// idempotencyKey will return the key to use for the request (empty if not a write request)
+func (c *Client) idempotencyKey(ctx context.Context, httpMethod string) string {
+	if httpMethod != http.MethodPost && httpMethod != http.MethodPut {
+		return ""
+	}
+	if key := IdempotencyKeyFromContext(ctx); len(key) > 0 {
+		return key
+	}
+	if c.options.idempotencyKeyGenerator != nil {
+		return c.options.idempotencyKeyGenerator()
+	}
+	return ""
+}
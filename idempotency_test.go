@@ -0,0 +1,129 @@
+package tonicpow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const testIdempotencyKey = "order-12345"
+
+// TestNewIdempotencyKey will test the method NewIdempotencyKey()
+func TestNewIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	key := NewIdempotencyKey()
+	assert.Equal(t, 32, len(key))
+	assert.NotEqual(t, key, NewIdempotencyKey())
+}
+
+// TestContextWithIdempotencyKey will test the method ContextWithIdempotencyKey()
+func TestContextWithIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", IdempotencyKeyFromContext(context.Background()))
+
+	ctx := ContextWithIdempotencyKey(context.Background(), testIdempotencyKey)
+	assert.Equal(t, testIdempotencyKey, IdempotencyKeyFromContext(ctx))
+}
+
+// TestClient_IdempotencyKey will test sending idempotency keys on requests
+func TestClient_IdempotencyKey(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("conversion key is reused across retries", func(t *testing.T) {
+		client, err := newTestClient(WithRetryPolicy(newTestRetryPolicy()))
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelConversion)
+
+		var keys []string
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodPost, endpoint, func(req *http.Request) (*http.Response, error) {
+			keys = append(keys, req.Header.Get(headerIdempotencyKey))
+			if len(keys) == 1 {
+				return httpmock.NewStringResponse(http.StatusBadGateway, `{"message":"bad gateway"}`), nil
+			}
+			return httpmock.NewJsonResponse(http.StatusCreated, newTestConversion())
+		})
+
+		var conversion *Conversion
+		var response *StandardResponse
+		conversion, response, err = client.CreateConversion(
+			WithGoalID(testGoalID),
+			WithTncpwSession(testTncpwSession),
+			WithIdempotencyKey(testIdempotencyKey),
+		)
+		assert.NoError(t, err)
+		assert.NotNil(t, conversion)
+		assert.Equal(t, testIdempotencyKey, response.IdempotencyKey)
+		assert.Equal(t, []string{testIdempotencyKey, testIdempotencyKey}, keys)
+	})
+
+	t.Run("goal key from context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelGoal)
+
+		var key string
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodPost, endpoint, func(req *http.Request) (*http.Response, error) {
+			key = req.Header.Get(headerIdempotencyKey)
+			return httpmock.NewJsonResponse(http.StatusCreated, newTestGoal())
+		})
+
+		goal := newTestGoal()
+		var response *StandardResponse
+		response, err = client.CreateGoalWithContext(
+			ContextWithIdempotencyKey(context.Background(), testIdempotencyKey), goal,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, testIdempotencyKey, key)
+		assert.Equal(t, testIdempotencyKey, response.IdempotencyKey)
+	})
+
+	t.Run("generated key (write requests only)", func(t *testing.T) {
+		client, err := newTestClient(WithIdempotencyKeyGenerator(func() string {
+			return "generated-key"
+		}))
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		campaign := newTestCampaign()
+		createEndpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelCampaign)
+		getEndpoint := fmt.Sprintf(
+			"%s/%s/details/?%s=%d", EnvironmentDevelopment.apiURL,
+			modelCampaign, fieldID, campaign.ID,
+		)
+
+		keys := map[string]string{}
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodPost, createEndpoint, func(req *http.Request) (*http.Response, error) {
+			keys[req.Method] = req.Header.Get(headerIdempotencyKey)
+			return httpmock.NewJsonResponse(http.StatusCreated, campaign)
+		})
+		httpmock.RegisterResponder(http.MethodGet, getEndpoint, func(req *http.Request) (*http.Response, error) {
+			keys[req.Method] = req.Header.Get(headerIdempotencyKey)
+			return httpmock.NewJsonResponse(http.StatusOK, campaign)
+		})
+
+		var response *StandardResponse
+		response, err = client.CreateCampaign(campaign)
+		assert.NoError(t, err)
+		assert.Equal(t, "generated-key", response.IdempotencyKey)
+
+		_, response, err = client.GetCampaign(campaign.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "", response.IdempotencyKey)
+
+		assert.Equal(t, "generated-key", keys[http.MethodPost])
+		assert.Equal(t, "", keys[http.MethodGet])
+	})
+}
@@ -1,44 +1,80 @@
 package tonicpow
 
-import "github.com/go-resty/resty/v2"
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+)
 
 // AdvertiserService is the advertiser requests
 type AdvertiserService interface {
 	GetAdvertiserProfile(profileID uint64) (profile *AdvertiserProfile, response *StandardResponse, err error)
+	GetAdvertiserProfileWithContext(ctx context.Context, profileID uint64) (profile *AdvertiserProfile, response *StandardResponse, err error)
 	ListAppsByAdvertiserProfile(profileID uint64, page, resultsPerPage int, sortBy, sortOrder string) (apps *AppResults, response *StandardResponse, err error)
+	ListAppsByAdvertiserProfileIterator(ctx context.Context, profileID uint64, resultsPerPage int, sortBy, sortOrder string, opts ...IteratorOps) *AppIterator
+	ListAppsByAdvertiserProfileWithContext(ctx context.Context, profileID uint64, page, resultsPerPage int, sortBy, sortOrder string) (apps *AppResults, response *StandardResponse, err error)
+	ListAppsByAdvertiserProfileWithQuery(ctx context.Context, profileID uint64, query ListAppsQuery) (apps *AppResults, response *StandardResponse, err error)
 	ListCampaignsByAdvertiserProfile(profileID uint64, page, resultsPerPage int, sortBy, sortOrder string) (campaigns *CampaignResults, response *StandardResponse, err error)
+	ListCampaignsByAdvertiserProfileIterator(ctx context.Context, profileID uint64, resultsPerPage int, sortBy, sortOrder string, opts ...IteratorOps) *CampaignIterator
+	ListCampaignsByAdvertiserProfileWithContext(ctx context.Context, profileID uint64, page, resultsPerPage int, sortBy, sortOrder string) (campaigns *CampaignResults, response *StandardResponse, err error)
+	ListCampaignsByAdvertiserProfileWithQuery(ctx context.Context, profileID uint64, query ListAdvertiserCampaignsQuery) (campaigns *CampaignResults, response *StandardResponse, err error)
 	UpdateAdvertiserProfile(profile *AdvertiserProfile) (*StandardResponse, error)
+	UpdateAdvertiserProfileWithContext(ctx context.Context, profile *AdvertiserProfile) (*StandardResponse, error)
 }
 
 // CampaignService is the campaign requests
 type CampaignService interface {
 	CampaignsFeed(feedType FeedType) (feed string, response *StandardResponse, err error)
+	CampaignsFeedItems(feedType FeedType) (items []*FeedItem, response *StandardResponse, err error)
+	CampaignsFeedItemsWithContext(ctx context.Context, feedType FeedType) (items []*FeedItem, response *StandardResponse, err error)
+	CampaignsFeedWithContext(ctx context.Context, feedType FeedType) (feed string, response *StandardResponse, err error)
 	CreateCampaign(campaign *Campaign) (*StandardResponse, error)
+	CreateCampaignWithContext(ctx context.Context, campaign *Campaign) (*StandardResponse, error)
 	GetCampaign(campaignID uint64) (campaign *Campaign, response *StandardResponse, err error)
+	GetCampaignWithContext(ctx context.Context, campaignID uint64) (campaign *Campaign, response *StandardResponse, err error)
 	GetCampaignBySlug(slug string) (campaign *Campaign, response *StandardResponse, err error)
+	GetCampaignBySlugWithContext(ctx context.Context, slug string) (campaign *Campaign, response *StandardResponse, err error)
 	ListCampaigns(page, resultsPerPage int, sortBy, sortOrder, searchQuery string, minimumBalance uint64, includeExpired bool) (results *CampaignResults, response *StandardResponse, err error)
+	ListCampaignsIterator(ctx context.Context, resultsPerPage int, sortBy, sortOrder, searchQuery string, minimumBalance uint64, includeExpired bool, opts ...IteratorOps) *CampaignIterator
+	ListCampaignsWithContext(ctx context.Context, page, resultsPerPage int, sortBy, sortOrder, searchQuery string, minimumBalance uint64, includeExpired bool) (results *CampaignResults, response *StandardResponse, err error)
+	ListCampaignsWithQuery(ctx context.Context, query ListCampaignsQuery) (results *CampaignResults, response *StandardResponse, err error)
 	ListCampaignsByURL(targetURL string, page, resultsPerPage int, sortBy, sortOrder string) (results *CampaignResults, response *StandardResponse, err error)
+	ListCampaignsByURLIterator(ctx context.Context, targetURL string, resultsPerPage int, sortBy, sortOrder string, opts ...IteratorOps) *CampaignIterator
+	ListCampaignsByURLWithContext(ctx context.Context, targetURL string, page, resultsPerPage int, sortBy, sortOrder string) (results *CampaignResults, response *StandardResponse, err error)
 	UpdateCampaign(campaign *Campaign) (response *StandardResponse, err error)
+	UpdateCampaignWithContext(ctx context.Context, campaign *Campaign) (response *StandardResponse, err error)
 }
 
 // ConversionService is the conversion requests
 type ConversionService interface {
 	CancelConversion(conversionID uint64, cancelReason string) (conversion *Conversion, response *StandardResponse, err error)
+	CancelConversionWithContext(ctx context.Context, conversionID uint64, cancelReason string) (conversion *Conversion, response *StandardResponse, err error)
 	CreateConversion(opts ...ConversionOps) (conversion *Conversion, response *StandardResponse, err error)
+	CreateConversionForRequest(r *http.Request, opts ...ConversionOps) (conversion *Conversion, response *StandardResponse, err error)
+	CreateConversionWithContext(ctx context.Context, opts ...ConversionOps) (conversion *Conversion, response *StandardResponse, err error)
+	CreateConversions(ctx context.Context, requests []ConversionRequest, options BatchOptions) []ConversionResult
 	GetConversion(conversionID uint64) (conversion *Conversion, response *StandardResponse, err error)
+	GetConversionWithContext(ctx context.Context, conversionID uint64) (conversion *Conversion, response *StandardResponse, err error)
+	WaitForConversion(ctx context.Context, conversionID uint64, options WaitOptions) (conversion *Conversion, err error)
 }
 
 // GoalService is the goal requests
 type GoalService interface {
 	CreateGoal(goal *Goal) (*StandardResponse, error)
+	CreateGoalWithContext(ctx context.Context, goal *Goal) (*StandardResponse, error)
 	DeleteGoal(goalID uint64) (bool, *StandardResponse, error)
+	DeleteGoalWithContext(ctx context.Context, goalID uint64) (bool, *StandardResponse, error)
 	GetGoal(goalID uint64) (goal *Goal, response *StandardResponse, err error)
+	GetGoalWithContext(ctx context.Context, goalID uint64) (goal *Goal, response *StandardResponse, err error)
 	UpdateGoal(goal *Goal) (*StandardResponse, error)
+	UpdateGoalWithContext(ctx context.Context, goal *Goal) (*StandardResponse, error)
 }
 
 // RateService is the rate requests
 type RateService interface {
 	GetCurrentRate(currency string, customAmount float64) (rate *Rate, response *StandardResponse, err error)
+	GetCurrentRateWithContext(ctx context.Context, currency string, customAmount float64) (rate *Rate, response *StandardResponse, err error)
 }
 
 // ClientInterface is the Tonicpow client interface
@@ -51,6 +87,8 @@ type ClientInterface interface {
 	GetEnvironment() Environment
 	GetUserAgent() string
 	Options() *ClientOptions
+	RateLimiter() *RateLimiter
 	Request(httpMethod string, requestEndpoint string, data interface{}, expectedCode int) (response *StandardResponse, err error)
+	RequestWithContext(ctx context.Context, httpMethod string, requestEndpoint string, data interface{}, expectedCode int) (response *StandardResponse, err error)
 	WithCustomHTTPClient(client *resty.Client) *Client
 }
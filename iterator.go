@@ -0,0 +1,268 @@
+package tonicpow
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// IteratorOps allow functional options to be supplied
+// that overwrite default iterator options.
+type IteratorOps func(o *iteratorOptions)
+
+// iteratorOptions holds all the configuration for the iterator
+type iteratorOptions struct {
+	prefetch  int // Number of pages to fetch ahead (0 = fetch on demand)
+	startPage int // First page to fetch
+}
+
+// WithPrefetch will fetch up to the given number of pages ahead in the background
+// Pages are fetched on demand by default.
+func WithPrefetch(pages int) IteratorOps {
+	return func(o *iteratorOptions) {
+		o.prefetch = pages
+	}
+}
+
+// WithStartPage will start the iterator at the given page
+// Default is the first page.
+func WithStartPage(page int) IteratorOps {
+	return func(o *iteratorOptions) {
+		o.startPage = page
+	}
+}
+
+// pageFetcher will fetch the results (and the pagination metadata) for the given page and page size
+type pageFetcher[T any] func(ctx context.Context, page, resultsPerPage int) ([]T, pageInfo, error)
+
+// pagedResults is a list response with pagination metadata (IE: CampaignResults)
+type pagedResults[T any] interface {
+	page() ([]T, pageInfo)
+}
+
+// resultsPage will return the items & pagination metadata of a list response (or the error)
+func resultsPage[T any, R pagedResults[T]](results R, err error) ([]T, pageInfo, error) {
+	if err != nil {
+		return nil, pageInfo{}, err
+	}
+	items, info := results.page()
+	return items, info, nil
+}
+
+// pageInfo is the pagination metadata returned with a page of results
+type pageInfo struct {
+	currentPage    int // Page returned by the API (0 if unknown)
+	results        int // Number of results on the page (0 if unknown)
+	resultsPerPage int // Page size used by the API (0 if unknown)
+}
+
+// isLastPage will return true if there are no pages after this one
+//
+// The page size and count reported by the API are used when set (the API may cap
+// the page size below the requested size), otherwise the requested size is used.
+func (p pageInfo) isLastPage(items, requestedPerPage int) bool {
+	if items == 0 {
+		return true
+	}
+	pageSize := requestedPerPage
+	if p.resultsPerPage > 0 {
+		pageSize = p.resultsPerPage
+	}
+	count := items
+	if p.results > 0 {
+		count = p.results
+	}
+	return count < pageSize
+}
+
+// nextPage will return the page after this one (using the page returned by the API if set)
+func (p pageInfo) nextPage(requested int) int {
+	if p.currentPage > 0 {
+		return p.currentPage + 1
+	}
+	return requested + 1
+}
+
+// page will return the campaigns & pagination metadata (implements pagedResults)
+func (r *CampaignResults) page() ([]*Campaign, pageInfo) {
+	if r == nil {
+		return nil, pageInfo{}
+	}
+	return r.Campaigns, pageInfo{currentPage: r.CurrentPage, results: r.Results, resultsPerPage: r.ResultsPerPage}
+}
+
+// page will return the apps & pagination metadata (implements pagedResults)
+func (r *AppResults) page() ([]*App, pageInfo) {
+	if r == nil {
+		return nil, pageInfo{}
+	}
+	return r.Apps, pageInfo{currentPage: r.CurrentPage, results: r.Results, resultsPerPage: r.ResultsPerPage}
+}
+
+// iteratorPage is a fetched page (used for prefetching)
+type iteratorPage[T any] struct {
+	err   error
+	info  pageInfo
+	items []T
+}
+
+// Iterator is a lazy iterator over paginated list results
+//
+// Pages are fetched as needed and the iterator stops after the last page.
+//
+//	it := client.ListCampaignsIterator(ctx, 25, "", "", "", 0, false)
+//	defer it.Close()
+//	for it.Next() {
+//		campaign := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	cancel         context.CancelFunc   // Stops the iterator (and prefetching)
+	closeOnce      sync.Once            // Guards closing the iterator
+	ctx            context.Context      // Context for fetching pages
+	current        T                    // Current value
+	done           bool                 // No more pages to fetch
+	err            error                // Error from fetching a page
+	fetch          pageFetcher[T]       // Fetches a page of results
+	items          []T                  // Remaining items on the current page
+	page           int                  // Next page to fetch (on demand)
+	pages          chan iteratorPage[T] // Prefetched pages (nil if prefetch is disabled)
+	resultsPerPage int                  // Requested results per page (if the API does not report the page size)
+	wg             sync.WaitGroup       // Waits for the prefetch goroutine
+}
+
+// CampaignIterator is an iterator over campaign results
+type CampaignIterator = Iterator[*Campaign]
+
+// AppIterator is an iterator over app results
+type AppIterator = Iterator[*App]
+
+// newIterator will return a new iterator using the fetcher
+//
+// The page size is defaulted here (once) and passed to every fetch, so the size sent to
+// the API is the size used to find the last page
+func newIterator[T any](ctx context.Context, resultsPerPage int,
+	fetch pageFetcher[T], opts ...IteratorOps) *Iterator[T] {
+
+	options := &iteratorOptions{startPage: 1}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.startPage < 1 {
+		options.startPage = 1
+	}
+	if resultsPerPage <= 0 {
+		resultsPerPage = defaultResultsPerPage
+	}
+
+	it := &Iterator[T]{
+		fetch:          fetch,
+		page:           options.startPage,
+		resultsPerPage: resultsPerPage,
+	}
+	it.ctx, it.cancel = context.WithCancel(ctx)
+
+	// Start prefetching (bounded by the channel size)
+	if options.prefetch > 0 {
+		it.pages = make(chan iteratorPage[T], options.prefetch)
+		it.wg.Add(1)
+		go it.prefetch(options.startPage)
+	}
+	return it
+}
+
+// Next will advance to the next value, returning false when there are no more values or an error occurred
+func (it *Iterator[T]) Next() bool {
+	for len(it.items) == 0 {
+		if it.done {
+			var zero T
+			it.current = zero
+			return false
+		}
+		it.nextPage()
+	}
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Value will return the current value
+func (it *Iterator[T]) Value() T {
+	return it.current
+}
+
+// Err will return the error (if any) that stopped the iterator
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Close will stop the iterator (and any prefetching), it is safe to call multiple times
+func (it *Iterator[T]) Close() {
+	it.closeOnce.Do(func() {
+		it.cancel()
+		it.wg.Wait()
+		it.done = true
+		it.items = nil
+	})
+}
+
+// All will return a range-over-func sequence of values and errors
+//
+// The sequence stops after yielding an error, and the iterator is closed when the loop ends
+func (it *Iterator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer it.Close()
+		for it.Next() {
+			if !yield(it.Value(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// nextPage will load the next page of results (from the prefetch channel or on demand)
+func (it *Iterator[T]) nextPage() {
+	var p iteratorPage[T]
+	if it.pages != nil {
+		var ok bool
+		if p, ok = <-it.pages; !ok {
+			it.done = true
+			return
+		}
+	} else {
+		p.items, p.info, p.err = it.fetch(it.ctx, it.page, it.resultsPerPage)
+		it.page = p.info.nextPage(it.page)
+	}
+	if p.err != nil {
+		it.err = p.err
+		it.done = true
+		return
+	}
+	if p.info.isLastPage(len(p.items), it.resultsPerPage) {
+		it.done = true
+	}
+	it.items = p.items
+}
+
+// prefetch will fetch pages in the background until the last page, an error or the iterator is closed
+func (it *Iterator[T]) prefetch(page int) {
+	defer it.wg.Done()
+	defer close(it.pages)
+	for {
+		items, info, err := it.fetch(it.ctx, page, it.resultsPerPage)
+		select {
+		case it.pages <- iteratorPage[T]{err: err, info: info, items: items}:
+		case <-it.ctx.Done():
+			return
+		}
+		if err != nil || info.isLastPage(len(items), it.resultsPerPage) {
+			return
+		}
+		page = info.nextPage(page)
+	}
+}
@@ -0,0 +1,335 @@
+package tonicpow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestPageFetcher will return a fetcher over the given number of items (and the pages requested)
+func newTestPageFetcher(total int, failOnPage int) (pageFetcher[int], *[]int) {
+	var mu sync.Mutex
+	var requested []int
+	return func(_ context.Context, page, _ int) ([]int, pageInfo, error) {
+		mu.Lock()
+		requested = append(requested, page)
+		mu.Unlock()
+		if page == failOnPage {
+			return nil, pageInfo{}, errors.New("page failed")
+		}
+		var items []int
+		for i := (page - 1) * 2; i < page*2 && i < total; i++ {
+			items = append(items, i)
+		}
+		return items, pageInfo{}, nil
+	}, &requested
+}
+
+// TestIterator will test the Iterator type
+func TestIterator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("iterate all pages", func(t *testing.T) {
+		fetch, requested := newTestPageFetcher(5, 0)
+		it := newIterator(context.Background(), 2, fetch)
+		defer it.Close()
+
+		var values []int
+		for it.Next() {
+			values = append(values, it.Value())
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, values)
+		assert.Equal(t, []int{1, 2, 3}, *requested)
+		assert.Equal(t, 0, it.Value())
+		assert.False(t, it.Next())
+	})
+
+	t.Run("full last page requests an empty page", func(t *testing.T) {
+		fetch, requested := newTestPageFetcher(4, 0)
+		it := newIterator(context.Background(), 2, fetch)
+		defer it.Close()
+
+		var count int
+		for it.Next() {
+			count++
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, 4, count)
+		assert.Equal(t, []int{1, 2, 3}, *requested)
+	})
+
+	t.Run("default page size is sent to the fetcher", func(t *testing.T) {
+		var sizes []int
+		it := newIterator(context.Background(), 0, func(_ context.Context, _, resultsPerPage int) ([]int, pageInfo, error) {
+			sizes = append(sizes, resultsPerPage)
+			return []int{1}, pageInfo{}, nil
+		})
+		defer it.Close()
+
+		assert.True(t, it.Next())
+		assert.False(t, it.Next())
+		assert.Equal(t, []int{defaultResultsPerPage}, sizes)
+	})
+
+	t.Run("api caps the page size", func(t *testing.T) {
+		var requested []int
+		it := newIterator(context.Background(), 5, func(_ context.Context, page, _ int) ([]int, pageInfo, error) {
+			requested = append(requested, page)
+			var items []int
+			for i := (page - 1) * 2; i < page*2 && i < 5; i++ {
+				items = append(items, i)
+			}
+			return items, pageInfo{currentPage: page, results: len(items), resultsPerPage: 2}, nil
+		})
+		defer it.Close()
+
+		var values []int
+		for it.Next() {
+			values = append(values, it.Value())
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, values)
+		assert.Equal(t, []int{1, 2, 3}, requested)
+	})
+
+	t.Run("api caps the page size (prefetch)", func(t *testing.T) {
+		it := newIterator(context.Background(), 5, func(_ context.Context, page, _ int) ([]int, pageInfo, error) {
+			var items []int
+			for i := (page - 1) * 2; i < page*2 && i < 5; i++ {
+				items = append(items, i)
+			}
+			return items, pageInfo{currentPage: page, results: len(items), resultsPerPage: 2}, nil
+		}, WithPrefetch(1))
+		defer it.Close()
+
+		var values []int
+		for it.Next() {
+			values = append(values, it.Value())
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, values)
+	})
+
+	t.Run("fetches lazily", func(t *testing.T) {
+		fetch, requested := newTestPageFetcher(10, 0)
+		it := newIterator(context.Background(), 2, fetch)
+		defer it.Close()
+
+		assert.Empty(t, *requested)
+		assert.True(t, it.Next())
+		assert.Equal(t, []int{1}, *requested)
+		assert.True(t, it.Next())
+		assert.Equal(t, []int{1}, *requested)
+		assert.True(t, it.Next())
+		assert.Equal(t, []int{1, 2}, *requested)
+	})
+
+	t.Run("error stops the iterator", func(t *testing.T) {
+		fetch, _ := newTestPageFetcher(10, 2)
+		it := newIterator(context.Background(), 2, fetch)
+		defer it.Close()
+
+		var values []int
+		for it.Next() {
+			values = append(values, it.Value())
+		}
+		assert.EqualError(t, it.Err(), "page failed")
+		assert.Equal(t, []int{0, 1}, values)
+		assert.False(t, it.Next())
+	})
+
+	t.Run("start page", func(t *testing.T) {
+		fetch, requested := newTestPageFetcher(5, 0)
+		it := newIterator(context.Background(), 2, fetch, WithStartPage(2))
+		defer it.Close()
+
+		var values []int
+		for it.Next() {
+			values = append(values, it.Value())
+		}
+		assert.Equal(t, []int{2, 3, 4}, values)
+		assert.Equal(t, []int{2, 3}, *requested)
+	})
+
+	t.Run("prefetch", func(t *testing.T) {
+		fetch, _ := newTestPageFetcher(7, 0)
+		it := newIterator(context.Background(), 2, fetch, WithPrefetch(2))
+		defer it.Close()
+
+		var values []int
+		for it.Next() {
+			values = append(values, it.Value())
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, values)
+	})
+
+	t.Run("prefetch error", func(t *testing.T) {
+		fetch, _ := newTestPageFetcher(10, 3)
+		it := newIterator(context.Background(), 2, fetch, WithPrefetch(1))
+		defer it.Close()
+
+		var values []int
+		for it.Next() {
+			values = append(values, it.Value())
+		}
+		assert.EqualError(t, it.Err(), "page failed")
+		assert.Equal(t, []int{0, 1, 2, 3}, values)
+	})
+
+	t.Run("close stops prefetching", func(t *testing.T) {
+		fetch, _ := newTestPageFetcher(1000, 0)
+		it := newIterator(context.Background(), 2, fetch, WithPrefetch(1))
+		assert.True(t, it.Next())
+		it.Close()
+		it.Close()
+		assert.False(t, it.Next())
+	})
+
+	t.Run("range over all", func(t *testing.T) {
+		fetch, _ := newTestPageFetcher(5, 0)
+		var values []int
+		for value, err := range newIterator(context.Background(), 2, fetch).All() {
+			assert.NoError(t, err)
+			values = append(values, value)
+		}
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, values)
+	})
+
+	t.Run("range over all (break)", func(t *testing.T) {
+		fetch, requested := newTestPageFetcher(100, 0)
+		var values []int
+		for value := range newIterator(context.Background(), 2, fetch).All() {
+			if value == 3 {
+				break
+			}
+			values = append(values, value)
+		}
+		assert.Equal(t, []int{0, 1, 2}, values)
+		assert.Equal(t, []int{1, 2}, *requested)
+	})
+
+	t.Run("range over all (error)", func(t *testing.T) {
+		fetch, _ := newTestPageFetcher(10, 2)
+		var errs []error
+		var values []int
+		for value, err := range newIterator(context.Background(), 2, fetch).All() {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			values = append(values, value)
+		}
+		assert.Equal(t, []int{0, 1}, values)
+		if assert.Len(t, errs, 1) {
+			assert.EqualError(t, errs[0], "page failed")
+		}
+	})
+}
+
+// mockResponsePages will mock a campaign results endpoint for each page
+func mockResponsePages(t *testing.T, endpoint func(page int) string, pages ...*CampaignResults) {
+	httpmock.Reset()
+	for i, results := range pages {
+		data, err := json.Marshal(results)
+		assert.NoError(t, err)
+		httpmock.RegisterResponder(http.MethodGet, endpoint(i+1), httpmock.NewStringResponder(http.StatusOK, string(data)))
+	}
+}
+
+// TestClient_ListCampaignsByAdvertiserProfileIterator will test the method ListCampaignsByAdvertiserProfileIterator()
+func TestClient_ListCampaignsByAdvertiserProfileIterator(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := func(page int) string {
+		return fmt.Sprintf(
+			"%s/%s/%s/%d?%s=%d&%s=%d&%s=%s&%s=%s",
+			EnvironmentDevelopment.apiURL, modelAdvertiser, modelCampaign, testAdvertiserID,
+			fieldCurrentPage, page,
+			fieldResultsPerPage, 2,
+			fieldSortBy, SortByFieldBalance,
+			fieldSortOrder, SortOrderAsc,
+		)
+	}
+
+	t.Run("iterate all campaigns", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		first := newTestCampaignResults(1, 2)
+		first.Campaigns = append(first.Campaigns, newTestCampaign())
+		first.Results = len(first.Campaigns)
+		mockResponsePages(t, endpoint, first, newTestCampaignResults(2, 2))
+
+		it := client.ListCampaignsByAdvertiserProfileIterator(
+			context.Background(), testAdvertiserID, 2, SortByFieldBalance, SortOrderAsc,
+		)
+		defer it.Close()
+
+		var count int
+		for it.Next() {
+			assert.Equal(t, testCampaignID, it.Value().ID)
+			count++
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		mockResponsePages(t, endpoint)
+		httpmock.RegisterResponder(http.MethodGet, endpoint(1), httpmock.NewStringResponder(
+			http.StatusNotFound, `{"code":404,"message":"not found"}`,
+		))
+
+		var errs []error
+		for _, err = range client.ListCampaignsByAdvertiserProfileIterator(
+			context.Background(), testAdvertiserID, 2, SortByFieldBalance, SortOrderAsc,
+		).All() {
+			errs = append(errs, err)
+		}
+		if assert.Len(t, errs, 1) {
+			assert.ErrorIs(t, errs[0], ErrNotFound)
+		}
+	})
+
+	t.Run("default page size", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		mockResponsePages(t, endpoint)
+		httpmock.RegisterResponder(http.MethodGet, strings.Replace(
+			endpoint(1), fieldResultsPerPage+"=2", fmt.Sprintf("%s=%d", fieldResultsPerPage, defaultResultsPerPage), 1,
+		), httpmock.NewStringResponder(http.StatusOK, `{"campaigns":[{"id":1}],"current_page":1,"results":1}`))
+
+		var count int
+		for _, err = range client.ListCampaignsByAdvertiserProfileIterator(
+			context.Background(), testAdvertiserID, 0, SortByFieldBalance, SortOrderAsc,
+		).All() {
+			assert.NoError(t, err)
+			count++
+		}
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("missing profile id", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		it := client.ListCampaignsByAdvertiserProfileIterator(context.Background(), 0, 2, "", "")
+		defer it.Close()
+		assert.False(t, it.Next())
+		assert.ErrorIs(t, it.Err(), ErrValidation)
+	})
+}
@@ -0,0 +1,285 @@
+package tonicpow
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ListCampaignsQuery is the query for listing campaigns
+//
+// Zero values are left out when encoding, defaults are applied when the request is made
+type ListCampaignsQuery struct {
+	IncludeExpired bool
+	MinimumBalance uint64
+	Page           int
+	ResultsPerPage int
+	SearchQuery    string
+	SortBy         string
+	SortOrder      string
+	TargetURL      string
+}
+
+// ListAdvertiserCampaignsQuery is the query for listing the campaigns of an advertiser profile
+//
+// Zero values are left out when encoding, defaults are applied when the request is made
+type ListAdvertiserCampaignsQuery struct {
+	Page           int
+	ResultsPerPage int
+	SortBy         string
+	SortOrder      string
+}
+
+// ListAppsQuery is the query for listing apps
+//
+// Zero values are left out when encoding, defaults are applied when the request is made
+type ListAppsQuery struct {
+	Page           int
+	ResultsPerPage int
+	SortBy         string
+	SortOrder      string
+}
+
+// Values will return the query as url values
+func (q ListCampaignsQuery) Values() url.Values {
+	values := listValues(q.Page, q.ResultsPerPage, q.SortBy, q.SortOrder)
+	if q.IncludeExpired {
+		values.Set(fieldExpired, strconv.FormatBool(q.IncludeExpired))
+	}
+	if q.MinimumBalance > 0 {
+		values.Set(fieldMinimumBalance, strconv.FormatUint(q.MinimumBalance, 10))
+	}
+	if len(q.SearchQuery) > 0 {
+		values.Set(fieldSearchQuery, q.SearchQuery)
+	}
+	if len(q.TargetURL) > 0 {
+		values.Set(fieldTargetURL, q.TargetURL)
+	}
+	return values
+}
+
+// Encode will encode the query into a URL query string (sorted by key)
+func (q ListCampaignsQuery) Encode() string {
+	return q.Values().Encode()
+}
+
+// Decode will decode a URL query string (from Encode) into the query
+func (q *ListCampaignsQuery) Decode(query string) (err error) {
+	var values url.Values
+	if values, err = url.ParseQuery(query); err != nil {
+		return
+	}
+
+	var decoded ListCampaignsQuery
+	if decoded.Page, decoded.ResultsPerPage, err = decodePagination(values); err != nil {
+		return
+	}
+	if expired := values.Get(fieldExpired); len(expired) > 0 {
+		if decoded.IncludeExpired, err = strconv.ParseBool(expired); err != nil {
+			return &ValidationError{Field: fieldExpired, Message: "expired is not a valid boolean"}
+		}
+	}
+	if balance := values.Get(fieldMinimumBalance); len(balance) > 0 {
+		if decoded.MinimumBalance, err = strconv.ParseUint(balance, 10, 64); err != nil {
+			return &ValidationError{Field: fieldMinimumBalance, Message: "minimum balance is not a valid number"}
+		}
+	}
+	decoded.SearchQuery = values.Get(fieldSearchQuery)
+	decoded.SortBy = values.Get(fieldSortBy)
+	decoded.SortOrder = values.Get(fieldSortOrder)
+	decoded.TargetURL = values.Get(fieldTargetURL)
+
+	*q = decoded
+	return
+}
+
+// Validate will check the sort field, sort order and pagination
+func (q ListCampaignsQuery) Validate() error {
+	return validateList(q.Page, q.ResultsPerPage, q.SortBy, q.SortOrder, campaignSortFields)
+}
+
+// withDefaults will return the query with the default pagination & sorting applied
+func (q ListCampaignsQuery) withDefaults() ListCampaignsQuery {
+	q.Page, q.ResultsPerPage, q.SortBy, q.SortOrder = listDefaults(
+		q.Page, q.ResultsPerPage, q.SortBy, q.SortOrder,
+	)
+	return q
+}
+
+// Values will return the query as url values
+func (q ListAdvertiserCampaignsQuery) Values() url.Values {
+	return listValues(q.Page, q.ResultsPerPage, q.SortBy, q.SortOrder)
+}
+
+// Encode will encode the query into a URL query string (sorted by key)
+func (q ListAdvertiserCampaignsQuery) Encode() string {
+	return q.Values().Encode()
+}
+
+// Decode will decode a URL query string (from Encode) into the query
+func (q *ListAdvertiserCampaignsQuery) Decode(query string) (err error) {
+	var values url.Values
+	if values, err = url.ParseQuery(query); err != nil {
+		return
+	}
+
+	var decoded ListAdvertiserCampaignsQuery
+	if decoded.Page, decoded.ResultsPerPage, err = decodePagination(values); err != nil {
+		return
+	}
+	decoded.SortBy = values.Get(fieldSortBy)
+	decoded.SortOrder = values.Get(fieldSortOrder)
+
+	*q = decoded
+	return
+}
+
+// Validate will check the sort field, sort order and pagination
+func (q ListAdvertiserCampaignsQuery) Validate() error {
+	return validateList(q.Page, q.ResultsPerPage, q.SortBy, q.SortOrder, campaignSortFields)
+}
+
+// withDefaults will return the query with the default pagination & sorting applied
+func (q ListAdvertiserCampaignsQuery) withDefaults() ListAdvertiserCampaignsQuery {
+	q.Page, q.ResultsPerPage, q.SortBy, q.SortOrder = listDefaults(
+		q.Page, q.ResultsPerPage, q.SortBy, q.SortOrder,
+	)
+	return q
+}
+
+// Values will return the query as url values
+func (q ListAppsQuery) Values() url.Values {
+	return listValues(q.Page, q.ResultsPerPage, q.SortBy, q.SortOrder)
+}
+
+// Encode will encode the query into a URL query string (sorted by key)
+func (q ListAppsQuery) Encode() string {
+	return q.Values().Encode()
+}
+
+// Decode will decode a URL query string (from Encode) into the query
+func (q *ListAppsQuery) Decode(query string) (err error) {
+	var values url.Values
+	if values, err = url.ParseQuery(query); err != nil {
+		return
+	}
+
+	var decoded ListAppsQuery
+	if decoded.Page, decoded.ResultsPerPage, err = decodePagination(values); err != nil {
+		return
+	}
+	decoded.SortBy = values.Get(fieldSortBy)
+	decoded.SortOrder = values.Get(fieldSortOrder)
+
+	*q = decoded
+	return
+}
+
+// Validate will check the sort field, sort order and pagination
+func (q ListAppsQuery) Validate() error {
+	return validateList(q.Page, q.ResultsPerPage, q.SortBy, q.SortOrder, appSortFields)
+}
+
+// withDefaults will return the query with the default pagination & sorting applied
+func (q ListAppsQuery) withDefaults() ListAppsQuery {
+	q.Page, q.ResultsPerPage, q.SortBy, q.SortOrder = listDefaults(
+		q.Page, q.ResultsPerPage, q.SortBy, q.SortOrder,
+	)
+	return q
+}
+
+// listValues will return the url values for pagination & sorting (zero values are left out)
+func listValues(page, resultsPerPage int, sortBy, sortOrder string) url.Values {
+	values := url.Values{}
+	if page > 0 {
+		values.Set(fieldCurrentPage, strconv.Itoa(page))
+	}
+	if resultsPerPage > 0 {
+		values.Set(fieldResultsPerPage, strconv.Itoa(resultsPerPage))
+	}
+	if len(sortBy) > 0 {
+		values.Set(fieldSortBy, sortBy)
+	}
+	if len(sortOrder) > 0 {
+		values.Set(fieldSortOrder, sortOrder)
+	}
+	return values
+}
+
+// orderedQuery will encode the key & value pairs into a URL query string (in the given order)
+//
+// Used by the positional list methods, which send every parameter (including zero values)
+func orderedQuery(pairs ...string) string {
+	var b strings.Builder
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if b.Len() > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(url.QueryEscape(pairs[i]))
+		b.WriteByte('=')
+		b.WriteString(url.QueryEscape(pairs[i+1]))
+	}
+	return b.String()
+}
+
+// sortDefaults will apply the default sorting used by the positional list methods
+//
+// No sort field defaults to newest first (created_at desc), nothing else is changed
+func sortDefaults(sortBy, sortOrder string) (string, string) {
+	if len(sortBy) == 0 {
+		return SortByFieldCreatedAt, SortOrderDesc
+	}
+	return sortBy, sortOrder
+}
+
+// listDefaults will apply the default pagination & sorting
+//
+// No sort field defaults to newest first (created_at desc)
+func listDefaults(page, resultsPerPage int, sortBy, sortOrder string) (int, int, string, string) {
+	if page <= 0 {
+		page = 1
+	}
+	if resultsPerPage <= 0 {
+		resultsPerPage = defaultResultsPerPage
+	}
+	if len(sortBy) == 0 {
+		sortBy = SortByFieldCreatedAt
+		sortOrder = SortOrderDesc
+	} else if len(sortOrder) == 0 {
+		sortOrder = SortOrderDesc
+	}
+	return page, resultsPerPage, sortBy, sortOrder
+}
+
+// validateList will validate the pagination & sorting against the allowed sort fields
+func validateList(page, resultsPerPage int, sortBy, sortOrder string, sortFields []string) error {
+	if page < 0 {
+		return &ValidationError{Field: fieldCurrentPage, Message: "current page cannot be negative"}
+	}
+	if resultsPerPage < 0 {
+		return &ValidationError{Field: fieldResultsPerPage, Message: "results per page cannot be negative"}
+	}
+	if len(sortBy) > 0 && !isInList(strings.ToLower(sortBy), sortFields) {
+		return newInvalidSortByError(sortBy)
+	}
+	if len(sortOrder) > 0 && !isInList(strings.ToLower(sortOrder), sortOrders) {
+		return newInvalidSortOrderError(sortOrder)
+	}
+	return nil
+}
+
+// decodePagination will decode the page & results per page from url values
+func decodePagination(values url.Values) (page, resultsPerPage int, err error) {
+	if p := values.Get(fieldCurrentPage); len(p) > 0 {
+		if page, err = strconv.Atoi(p); err != nil {
+			err = &ValidationError{Field: fieldCurrentPage, Message: "current page is not a valid number"}
+			return
+		}
+	}
+	if r := values.Get(fieldResultsPerPage); len(r) > 0 {
+		if resultsPerPage, err = strconv.Atoi(r); err != nil {
+			err = &ValidationError{Field: fieldResultsPerPage, Message: "results per page is not a valid number"}
+		}
+	}
+	return
+}
@@ -0,0 +1,220 @@
+package tonicpow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestListCampaignsQuery_Encode will test the method Encode() and Decode()
+func TestListCampaignsQuery_Encode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip", func(t *testing.T) {
+		query := ListCampaignsQuery{
+			IncludeExpired: true,
+			MinimumBalance: 5000,
+			Page:           3,
+			ResultsPerPage: 10,
+			SearchQuery:    "cats & dogs",
+			SortBy:         SortByFieldBalance,
+			SortOrder:      SortOrderAsc,
+			TargetURL:      "https://tonicpow.com/?a=1&b=2",
+		}
+
+		encoded := query.Encode()
+		assert.Equal(t, "current_page=3&expired=true&minimum_balance=5000&query=cats+%26+dogs&results_per_page=10"+
+			"&sort_by=balance&sort_order=asc"+
+			"&target_url=https%3A%2F%2Ftonicpow.com%2F%3Fa%3D1%26b%3D2", encoded)
+
+		var decoded ListCampaignsQuery
+		assert.NoError(t, decoded.Decode(encoded))
+		assert.Equal(t, query, decoded)
+	})
+
+	t.Run("zero values are left out", func(t *testing.T) {
+		assert.Equal(t, "", ListCampaignsQuery{}.Encode())
+
+		var decoded ListCampaignsQuery
+		assert.NoError(t, decoded.Decode(""))
+		assert.Equal(t, ListCampaignsQuery{}, decoded)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		for _, encoded := range []string{
+			"current_page=one",
+			"results_per_page=1.5",
+			"minimum_balance=-1",
+			"expired=maybe",
+			"%zz",
+		} {
+			query := ListCampaignsQuery{Page: 9}
+			assert.Error(t, query.Decode(encoded), encoded)
+			assert.Equal(t, 9, query.Page)
+		}
+
+		var query ListCampaignsQuery
+		err := query.Decode("expired=maybe")
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+// TestListAdvertiserCampaignsQuery_Encode will test the method Encode() and Decode()
+func TestListAdvertiserCampaignsQuery_Encode(t *testing.T) {
+	t.Parallel()
+
+	query := ListAdvertiserCampaignsQuery{Page: 2, ResultsPerPage: 5, SortBy: SortByFieldBalance, SortOrder: SortOrderAsc}
+	encoded := query.Encode()
+	assert.Equal(t, "current_page=2&results_per_page=5&sort_by=balance&sort_order=asc", encoded)
+
+	var decoded ListAdvertiserCampaignsQuery
+	assert.NoError(t, decoded.Decode(encoded))
+	assert.Equal(t, query, decoded)
+	assert.Error(t, decoded.Decode("results_per_page=five"))
+}
+
+// TestListAppsQuery_Encode will test the method Encode() and Decode()
+func TestListAppsQuery_Encode(t *testing.T) {
+	t.Parallel()
+
+	query := ListAppsQuery{Page: 2, ResultsPerPage: 5, SortBy: SortByFieldName, SortOrder: SortOrderDesc}
+	encoded := query.Encode()
+	assert.Equal(t, "current_page=2&results_per_page=5&sort_by=name&sort_order=desc", encoded)
+
+	var decoded ListAppsQuery
+	assert.NoError(t, decoded.Decode(encoded))
+	assert.Equal(t, query, decoded)
+	assert.Error(t, decoded.Decode("current_page=two"))
+}
+
+// TestListQuery_Validate will test the method Validate()
+func TestListQuery_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ListCampaignsQuery{}.Validate())
+	assert.NoError(t, ListCampaignsQuery{SortBy: "Balance", SortOrder: "ASC"}.Validate())
+	assert.NoError(t, ListAppsQuery{SortBy: SortByFieldName}.Validate())
+
+	var validationErr *ValidationError
+	err := ListCampaignsQuery{SortBy: SortByFieldName}.Validate()
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, fieldSortBy, validationErr.Field)
+
+	err = ListAdvertiserCampaignsQuery{SortBy: SortByFieldName}.Validate()
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, fieldSortBy, validationErr.Field)
+
+	err = ListAppsQuery{SortBy: SortByFieldBalance}.Validate()
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, fieldSortBy, validationErr.Field)
+
+	err = ListCampaignsQuery{SortOrder: "sideways"}.Validate()
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, fieldSortOrder, validationErr.Field)
+
+	err = ListAppsQuery{Page: -1}.Validate()
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, fieldCurrentPage, validationErr.Field)
+
+	err = ListCampaignsQuery{ResultsPerPage: -1}.Validate()
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, fieldResultsPerPage, validationErr.Field)
+}
+
+// TestListQuery_withDefaults will test the method withDefaults()
+func TestListQuery_withDefaults(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, ListCampaignsQuery{
+		Page:           1,
+		ResultsPerPage: defaultResultsPerPage,
+		SortBy:         SortByFieldCreatedAt,
+		SortOrder:      SortOrderDesc,
+	}, ListCampaignsQuery{SortOrder: SortOrderAsc}.withDefaults())
+
+	assert.Equal(t, ListAppsQuery{
+		Page:           4,
+		ResultsPerPage: 5,
+		SortBy:         SortByFieldName,
+		SortOrder:      SortOrderDesc,
+	}, ListAppsQuery{Page: 4, ResultsPerPage: 5, SortBy: SortByFieldName}.withDefaults())
+}
+
+// TestOrderedQuery will test the method orderedQuery()
+func TestOrderedQuery(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", orderedQuery())
+	assert.Equal(t, "target_url=https%3A%2F%2Ftonicpow.com%2F%3Fa%3D1%26b%3D2&current_page=0&query=",
+		orderedQuery(fieldTargetURL, "https://tonicpow.com/?a=1&b=2", fieldCurrentPage, "0", fieldSearchQuery, ""))
+}
+
+// TestClient_ListCampaignsWithQuery will test the method ListCampaignsWithQuery()
+func TestClient_ListCampaignsWithQuery(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("special characters are encoded", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		values := url.Values{}
+		values.Set(fieldCurrentPage, "1")
+		values.Set(fieldResultsPerPage, "25")
+		values.Set(fieldSearchQuery, "cats & dogs")
+		values.Set(fieldSortBy, SortByFieldCreatedAt)
+		values.Set(fieldSortOrder, SortOrderDesc)
+		endpoint := fmt.Sprintf("%s/%s/list?%s", EnvironmentDevelopment.apiURL, modelCampaign, values.Encode())
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestCampaignResults(1, 25))
+		assert.NoError(t, err)
+
+		var results *CampaignResults
+		results, _, err = client.ListCampaignsWithQuery(context.Background(), ListCampaignsQuery{
+			ResultsPerPage: 25,
+			SearchQuery:    "cats & dogs",
+		})
+		assert.NoError(t, err)
+		if assert.NotNil(t, results) {
+			assert.Equal(t, testCampaignID, results.Campaigns[0].ID)
+		}
+	})
+
+	t.Run("target url with a query string", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		targetURL := "https://tonicpow.com/page?ref=a b&x=1"
+		values := url.Values{}
+		values.Set(fieldCurrentPage, "2")
+		values.Set(fieldResultsPerPage, "5")
+		values.Set(fieldSortBy, SortByFieldBalance)
+		values.Set(fieldSortOrder, SortOrderAsc)
+		values.Set(fieldTargetURL, targetURL)
+		endpoint := fmt.Sprintf("%s/%s/list?%s", EnvironmentDevelopment.apiURL, modelCampaign, values.Encode())
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestCampaignResults(2, 5))
+		assert.NoError(t, err)
+
+		var results *CampaignResults
+		results, _, err = client.ListCampaignsByURL(targetURL, 2, 5, SortByFieldBalance, SortOrderAsc)
+		assert.NoError(t, err)
+		assert.NotNil(t, results)
+	})
+
+	t.Run("invalid sort order", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		var results *CampaignResults
+		results, _, err = client.ListCampaignsWithQuery(context.Background(), ListCampaignsQuery{
+			SortBy:    SortByFieldBalance,
+			SortOrder: "up",
+		})
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Nil(t, results)
+	})
+}
@@ -0,0 +1,111 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Log attribute keys
+const (
+	logKeyAPIKey      = "api_key"
+	logKeyAttempt     = "attempt"
+	logKeyDelay       = "delay"
+	logKeyDuration    = "duration"
+	logKeyEndpoint    = "endpoint"
+	logKeyError       = "error"
+	logKeyMethod      = "method"
+	logKeyOperation   = "operation"
+	logKeyRequestGUID = "request_guid"
+	logKeyStatusCode  = "status_code"
+)
+
+// logEnabled will return true if the client should log at the given level
+func (c *Client) logEnabled(ctx context.Context, level slog.Level) bool {
+	return c.options.logger != nil && level >= c.options.logLevel && c.options.logger.Enabled(ctx, level)
+}
+
+// logResponse will log the result of a single request attempt
+//
+// Successful requests are logged at debug, client errors (4xx) at warn and all other failures at error
+func (c *Client) logResponse(ctx context.Context, apiRequest *APIRequest, attempt int,
+	response *StandardResponse, err error, elapsed time.Duration) {
+
+	level := slog.LevelDebug
+	if err != nil {
+		level = slog.LevelError
+		if errors.Is(err, context.Canceled) ||
+			(response != nil && response.StatusCode >= http.StatusBadRequest &&
+				response.StatusCode < http.StatusInternalServerError) {
+			level = slog.LevelWarn
+		}
+	}
+	if !c.logEnabled(ctx, level) {
+		return
+	}
+
+	attrs := c.logRequestAttrs(apiRequest.Method, apiRequest.Endpoint, apiRequest.Operation.Name)
+	attrs = append(attrs, slog.Int(logKeyAttempt, attempt), slog.Duration(logKeyDuration, elapsed))
+	if response != nil {
+		attrs = append(attrs, slog.Int(logKeyStatusCode, response.StatusCode))
+		if response.Error != nil && len(response.Error.RequestGUID) > 0 {
+			attrs = append(attrs, slog.String(logKeyRequestGUID, response.Error.RequestGUID))
+		}
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String(logKeyError, err.Error()))
+		c.options.logger.LogAttrs(ctx, level, "tonicpow api request failed", attrs...)
+		return
+	}
+	c.options.logger.LogAttrs(ctx, level, "tonicpow api request", attrs...)
+}
+
+// logRetry will log a retry attempt (warn)
+func (c *Client) logRetry(ctx context.Context, retry RetryInfo, operation string) {
+	if !c.logEnabled(ctx, slog.LevelWarn) {
+		return
+	}
+	attrs := c.logRequestAttrs(retry.Method, retry.Endpoint, operation)
+	attrs = append(attrs,
+		slog.Int(logKeyAttempt, retry.Attempt),
+		slog.Duration(logKeyDelay, retry.Delay),
+		slog.Int(logKeyStatusCode, retry.StatusCode),
+	)
+	if retry.Err != nil {
+		attrs = append(attrs, slog.String(logKeyError, retry.Err.Error()))
+	}
+	c.options.logger.LogAttrs(ctx, slog.LevelWarn, "retrying tonicpow api request", attrs...)
+}
+
+// logDecodeError will log a response body that could not be decoded (warn)
+func (c *Client) logDecodeError(ctx context.Context, apiRequest *APIRequest, statusCode int, err error) {
+	if !c.logEnabled(ctx, slog.LevelWarn) {
+		return
+	}
+	attrs := c.logRequestAttrs(apiRequest.Method, apiRequest.Endpoint, apiRequest.Operation.Name)
+	attrs = append(attrs, slog.Int(logKeyStatusCode, statusCode), slog.String(logKeyError, err.Error()))
+	c.options.logger.LogAttrs(ctx, slog.LevelWarn, "unable to decode tonicpow api error response", attrs...)
+}
+
+// logRequestAttrs will return the common attributes for a request (the API key is redacted)
+func (c *Client) logRequestAttrs(httpMethod, requestEndpoint, operation string) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String(logKeyMethod, httpMethod),
+		slog.String(logKeyEndpoint, requestEndpoint),
+		slog.String(logKeyAPIKey, redactAPIKey(c.options.apiKey)),
+	}
+	if len(operation) > 0 {
+		attrs = append(attrs, slog.String(logKeyOperation, operation))
+	}
+	return attrs
+}
+
+// redactAPIKey will redact the API key (only the last 4 characters are shown)
+func redactAPIKey(apiKey string) string {
+	if len(apiKey) <= 8 {
+		return "****"
+	}
+	return "****" + apiKey[len(apiKey)-4:]
+}
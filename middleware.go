@@ -0,0 +1,66 @@
+package tonicpow
+
+import (
+	"context"
+	"net/http"
+)
+
+// APIRequest is an outgoing API request (passed through the middleware chain)
+type APIRequest struct {
+	Attempt      int         // Attempt number (1 = first request, 0 in the call middleware)
+	Body         []byte      // JSON body (POST / PUT)
+	Endpoint     string      // Request endpoint (IE: /campaigns/details/?id=1)
+	ExpectedCode int         // Expected status code (0 will accept any status code)
+	Header       http.Header // Headers for the request (API key, user agent, custom headers, etc.)
+	Method       string      // HTTP method
+	Operation    Operation   // Service method that made the request (empty if Request was called directly)
+}
+
+// Operation is the service method that made the request and the resources involved
+type Operation struct {
+	AdvertiserProfileID uint64 // Advertiser profile (if known)
+	CampaignID          uint64 // Campaign (if known)
+	ConversionID        uint64 // Conversion (if known)
+	GoalID              uint64 // Goal (if known)
+	Name                string // Service method name (IE: GetCampaign)
+}
+
+// operationContextKey is the context key for the Operation
+type operationContextKey struct{}
+
+// withOperation will return a context with the Operation (set by the service methods)
+func withOperation(ctx context.Context, operation Operation) context.Context {
+	return context.WithValue(ctx, operationContextKey{}, operation)
+}
+
+// operationFromContext will return the Operation from the context (if set)
+func operationFromContext(ctx context.Context) Operation {
+	operation, _ := ctx.Value(operationContextKey{}).(Operation)
+	return operation
+}
+
+// RoundTripFunc will fire an APIRequest and return the response
+type RoundTripFunc func(ctx context.Context, req *APIRequest) (*StandardResponse, error)
+
+// Middleware wraps a RoundTripFunc (IE: logging, metrics, header injection, fault injection)
+//
+// Middleware is called for every attempt made by Client.Request (including retries),
+// unless it is set as call middleware (see: WithCallMiddleware)
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// forAttempt will return a copy of the request for the given attempt (headers are copied)
+func (r *APIRequest) forAttempt(attempt int) *APIRequest {
+	attemptRequest := *r
+	attemptRequest.Attempt = attempt
+	attemptRequest.Header = r.Header.Clone()
+	return &attemptRequest
+}
+
+// chainMiddleware will wrap the round trip with all the middleware
+// The first middleware is the outermost (runs first)
+func chainMiddleware(roundTrip RoundTripFunc, middleware []Middleware) RoundTripFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		roundTrip = middleware[i](roundTrip)
+	}
+	return roundTrip
+}
@@ -0,0 +1,146 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChainMiddleware will test the method chainMiddleware()
+func TestChainMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	newMiddleware := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(ctx context.Context, req *APIRequest) (*StandardResponse, error) {
+				calls = append(calls, name+":before")
+				response, err := next(ctx, req)
+				calls = append(calls, name+":after")
+				return response, err
+			}
+		}
+	}
+
+	roundTrip := chainMiddleware(func(ctx context.Context, req *APIRequest) (*StandardResponse, error) {
+		calls = append(calls, "request")
+		return &StandardResponse{StatusCode: http.StatusOK}, nil
+	}, []Middleware{newMiddleware("first"), newMiddleware("second")})
+
+	response, err := roundTrip(context.Background(), &APIRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, []string{
+		"first:before", "second:before", "request", "second:after", "first:after",
+	}, calls)
+}
+
+// TestClient_Request_Middleware will test the middleware on Request()
+func TestClient_Request_Middleware(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s/details/%d", EnvironmentDevelopment.apiURL, modelGoal, testGoalID)
+
+	t.Run("header injection", func(t *testing.T) {
+		var requests []*APIRequest
+		client, err := newTestClient(WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+			return func(ctx context.Context, req *APIRequest) (*StandardResponse, error) {
+				requests = append(requests, req)
+				req.Header.Set("X-Request-ID", "request-id-1")
+				return next(ctx, req)
+			}
+		}))
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		var requestID, apiKey string
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodGet, endpoint, func(req *http.Request) (*http.Response, error) {
+			requestID = req.Header.Get("X-Request-ID")
+			apiKey = req.Header.Get(fieldAPIKey)
+			return httpmock.NewJsonResponse(http.StatusOK, newTestGoal())
+		})
+
+		var goal *Goal
+		goal, _, err = client.GetGoal(testGoalID)
+		assert.NoError(t, err)
+		assert.NotNil(t, goal)
+		assert.Equal(t, "request-id-1", requestID)
+		assert.Equal(t, testAPIKey, apiKey)
+
+		assert.Equal(t, 1, len(requests))
+		assert.Equal(t, http.MethodGet, requests[0].Method)
+		assert.Equal(t, fmt.Sprintf("/%s/details/%d", modelGoal, testGoalID), requests[0].Endpoint)
+		assert.Equal(t, http.StatusOK, requests[0].ExpectedCode)
+		assert.Equal(t, Operation{Name: "GetGoal", GoalID: testGoalID}, requests[0].Operation)
+	})
+
+	t.Run("fault injection is retried", func(t *testing.T) {
+		attempts := 0
+		client, err := newTestClient(
+			WithRetryPolicy(newTestRetryPolicy()),
+			WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+				return func(ctx context.Context, req *APIRequest) (*StandardResponse, error) {
+					attempts++
+					if attempts == 1 {
+						return nil, errors.New("injected fault")
+					}
+					return next(ctx, req)
+				}
+			}),
+		)
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestGoal())
+		assert.NoError(t, err)
+
+		var goal *Goal
+		goal, _, err = client.GetGoal(testGoalID)
+		assert.NoError(t, err)
+		assert.NotNil(t, goal)
+		assert.Equal(t, 2, attempts)
+	})
+	t.Run("call middleware wraps every attempt", func(t *testing.T) {
+		var calls, attempts []int
+		var requestIDs []string
+		client, err := newTestClient(
+			WithRetryPolicy(newTestRetryPolicy()),
+			WithCallMiddleware(func(next RoundTripFunc) RoundTripFunc {
+				return func(ctx context.Context, req *APIRequest) (*StandardResponse, error) {
+					calls = append(calls, req.Attempt)
+					req.Header.Set("X-Request-ID", "request-id-1")
+					return next(ctx, req)
+				}
+			}),
+			WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+				return func(ctx context.Context, req *APIRequest) (*StandardResponse, error) {
+					attempts = append(attempts, req.Attempt)
+					requestIDs = append(requestIDs, req.Header.Get("X-Request-ID"))
+					if len(attempts) == 1 {
+						return nil, errors.New("injected fault")
+					}
+					return next(ctx, req)
+				}
+			}),
+		)
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestGoal())
+		assert.NoError(t, err)
+
+		var goal *Goal
+		goal, _, err = client.GetGoal(testGoalID)
+		assert.NoError(t, err)
+		assert.NotNil(t, goal)
+		assert.Equal(t, []int{0}, calls)
+		assert.Equal(t, []int{1, 2}, attempts)
+		assert.Equal(t, []string{"request-id-1", "request-id-1"}, requestIDs)
+	})
+}
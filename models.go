@@ -1,5 +1,7 @@
 package tonicpow
 
+import "time"
+
 // AdvertiserProfile is the advertiser_profile model (child of User)
 //
 // For more information: https://docs.tonicpow.com/#2f9ec542-0f88-4671-b47c-d0ee390af5ea
@@ -118,17 +120,30 @@ type CampaignResults struct {
 //
 // For more information: https://docs.tonicpow.com/#75c837d5-3336-4d87-a686-d80c6f8938b9
 type Conversion struct {
-	Amount           float64 `json:"amount,omitempty"`
-	CampaignID       uint64  `json:"campaign_id"`
-	CustomDimensions string  `json:"custom_dimensions"`
-	GoalID           uint64  `json:"goal_id"`
-	GoalName         string  `json:"goal_name,omitempty"`
-	ID               uint64  `json:"id,omitempty"`
-	PayoutAfter      string  `json:"payout_after,omitempty"`
-	Status           string  `json:"status"`
-	StatusData       string  `json:"status_data"`
-	TxID             string  `json:"tx_id"`
-	UserID           uint64  `json:"user_id"`
+	Amount           float64          `json:"amount,omitempty"`
+	CampaignID       uint64           `json:"campaign_id"`
+	CustomDimensions string           `json:"custom_dimensions"`
+	GoalID           uint64           `json:"goal_id"`
+	GoalName         string           `json:"goal_name,omitempty"`
+	ID               uint64           `json:"id,omitempty"`
+	PayoutAfter      string           `json:"payout_after,omitempty"`
+	Status           ConversionStatus `json:"status"`
+	StatusData       string           `json:"status_data"`
+	TxID             string           `json:"tx_id"`
+	UserID           uint64           `json:"user_id"`
+}
+
+// FeedItem is a campaign from a campaigns feed (RSS, Atom or JSON Feed)
+type FeedItem struct {
+	CampaignID   uint64    `json:"campaign_id,omitempty"`
+	CampaignSlug string    `json:"campaign_slug"`
+	Description  string    `json:"description"`
+	ID           string    `json:"id"`
+	ImageURL     string    `json:"image_url"`
+	Link         string    `json:"link"`
+	Published    time.Time `json:"published"`
+	Title        string    `json:"title"`
+	Updated      time.Time `json:"updated"`
 }
 
 // Goal is the goal model (child of Campaign)
@@ -0,0 +1,385 @@
+package tonicpow
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Errors returned by Money arithmetic
+var (
+	// ErrCurrencyMismatch is returned when combining amounts in different currencies
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+
+	// ErrMoneyOverflow is returned when an amount does not fit in Money
+	ErrMoneyOverflow = errors.New("money amount overflow")
+)
+
+// MoneyDecimals is the number of decimal places kept by Money (1 satoshi = 0.00000001 BSV)
+const MoneyDecimals = 8
+
+// moneyScale is the number of units in 1 (10^MoneyDecimals)
+const moneyScale int64 = 100_000_000
+
+// SatoshisPerBSV is the number of satoshis in 1 BSV
+const SatoshisPerBSV Satoshis = 100_000_000
+
+// Satoshis is an amount of BSV in satoshis
+type Satoshis int64
+
+// BSV will return the amount in BSV (exact)
+func (s Satoshis) BSV() Money {
+	return Money{currency: CurrencyBSV, units: int64(s)}
+}
+
+// String will return the amount (IE: 4200 sats)
+func (s Satoshis) String() string {
+	return strconv.FormatInt(int64(s), 10) + " sats"
+}
+
+// Money is an exact decimal amount (8 decimal places) in a currency
+//
+// Use Money for accounting (balances, payouts, purchase amounts) instead of float64.
+// It encodes to JSON as a number, compatible with the float64 fields of the models.
+// The zero value is 0 with no currency.
+type Money struct {
+	currency string // Lowercase currency code (IE: usd, bsv)
+	units    int64  // Amount in 10^-8
+}
+
+// ParseMoney will parse an exact decimal amount (IE: "12.34") in the currency
+//
+// More than 8 decimal places is an error (see: MoneyFromFloat for rounding)
+func ParseMoney(amount, currency string) (Money, error) {
+	units, err := parseMoneyUnits(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{currency: strings.ToLower(currency), units: units}, nil
+}
+
+// MoneyFromFloat will return the amount in the currency (rounded to 8 decimal places)
+func MoneyFromFloat(amount float64, currency string) Money {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return Money{currency: strings.ToLower(currency)}
+	}
+	units, err := parseMoneyUnits(strconv.FormatFloat(amount, 'f', MoneyDecimals, 64))
+	if err != nil {
+		// Out of range, saturate
+		if units = math.MaxInt64; amount < 0 {
+			units = math.MinInt64 + 1
+		}
+	}
+	return Money{currency: strings.ToLower(currency), units: units}
+}
+
+// Currency will return the currency code (lowercase)
+func (m Money) Currency() string {
+	return m.currency
+}
+
+// WithCurrency will return the amount in the currency (the amount is not converted)
+func (m Money) WithCurrency(currency string) Money {
+	m.currency = strings.ToLower(currency)
+	return m
+}
+
+// IsZero will return true if the amount is zero
+func (m Money) IsZero() bool {
+	return m.units == 0
+}
+
+// IsNegative will return true if the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.units < 0
+}
+
+// Cmp will compare the amounts (-1 if m < o, 0 if equal, +1 if m > o), the currencies are not compared
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m.units < o.units:
+		return -1
+	case m.units > o.units:
+		return 1
+	}
+	return 0
+}
+
+// Equal will return true if the amounts and currencies are the same
+func (m Money) Equal(o Money) bool {
+	return m == o
+}
+
+// Add will return m + o
+//
+// An amount without a currency can be added to any currency
+func (m Money) Add(o Money) (Money, error) {
+	currency, err := m.commonCurrency(o)
+	if err != nil {
+		return Money{}, err
+	}
+	sum := m.units + o.units
+	if (o.units > 0 && sum < m.units) || (o.units < 0 && sum > m.units) {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{currency: currency, units: sum}, nil
+}
+
+// Sub will return m - o
+//
+// An amount without a currency can be subtracted from any currency
+func (m Money) Sub(o Money) (Money, error) {
+	if o.units == math.MinInt64 {
+		return Money{}, ErrMoneyOverflow
+	}
+	return m.Add(o.Neg())
+}
+
+// Mul will return m * n
+func (m Money) Mul(n int64) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(m.units), big.NewInt(n))
+	if !product.IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{currency: m.currency, units: product.Int64()}, nil
+}
+
+// Neg will return -m
+func (m Money) Neg() Money {
+	m.units = -m.units
+	return m
+}
+
+// Round will return the amount rounded to the decimal places (IE: 2 for cents)
+//
+// Returns ErrMoneyOverflow if the rounded amount does not fit in Money
+func (m Money) Round(decimals int, mode RoundingMode) (Money, error) {
+	units := m.roundedUnits(decimals, mode)
+	if !units.IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+	m.units = units.Int64()
+	return m, nil
+}
+
+// Float64 will return the amount as a float64 (IE: for the float64 fields of the models)
+func (m Money) Float64() float64 {
+	f, _ := strconv.ParseFloat(m.Text(), 64)
+	return f
+}
+
+// Text will return the exact amount without trailing zeros (IE: 12.5)
+func (m Money) Text() string {
+	text := formatMoneyUnits(big.NewInt(m.units), MoneyDecimals)
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+	return text
+}
+
+// String will return the amount formatted for the currency (see: Format)
+func (m Money) String() string {
+	return m.Format()
+}
+
+// Format will return the amount formatted for the currency (IE: $1,234.50, €10.00, 0.00004200 BSV)
+//
+// The amount is rounded (half away from zero) to the decimal places of the currency
+func (m Money) Format() string {
+	if len(m.currency) == 0 {
+		return m.Text()
+	}
+	format := currencyFormatFor(m.currency)
+	text := groupThousands(formatMoneyUnits(m.roundedUnits(format.decimals, RoundHalfUp), format.decimals))
+
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	if len(format.symbol) > 0 {
+		return sign + format.symbol + text
+	}
+	return sign + text + " " + strings.ToUpper(m.currency)
+}
+
+// MarshalJSON will encode the amount as a JSON number (implements json.Marshaler)
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Text()), nil
+}
+
+// UnmarshalJSON will decode the amount from a JSON number or string (implements json.Unmarshaler)
+//
+// The currency is not changed (the API sends it in a separate field)
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	amount := string(bytes.Trim(data, `"`))
+	if len(amount) == 0 {
+		m.units = 0
+		return nil
+	}
+
+	units, err := parseMoneyUnits(amount)
+	if err != nil {
+		// Exponents & extra decimals (IE: 1e-9) are rounded to 8 decimal places
+		f, floatErr := strconv.ParseFloat(amount, 64)
+		if floatErr != nil || errors.Is(err, ErrMoneyOverflow) {
+			return err
+		} else if math.Abs(f) >= math.MaxInt64/float64(moneyScale) {
+			return fmt.Errorf("%w: %q", ErrMoneyOverflow, amount)
+		}
+		units = MoneyFromFloat(f, "").units
+	}
+	m.units = units
+	return nil
+}
+
+// roundedUnits will return the units rounded to the decimal places (the result may not fit in an int64)
+func (m Money) roundedUnits(decimals int, mode RoundingMode) *big.Int {
+	units := big.NewInt(m.units)
+	if decimals >= MoneyDecimals || decimals < 0 {
+		return units
+	}
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(MoneyDecimals-decimals)), nil)
+	rounded := divRound(units, pow, mode)
+	return rounded.Mul(rounded, pow)
+}
+
+// commonCurrency will return the currency of the result of combining the amounts
+func (m Money) commonCurrency(o Money) (string, error) {
+	switch {
+	case m.currency == o.currency, len(o.currency) == 0:
+		return m.currency, nil
+	case len(m.currency) == 0:
+		return o.currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, o.currency)
+}
+
+// parseMoneyUnits will parse a decimal amount into units of 10^-8
+func parseMoneyUnits(amount string) (int64, error) {
+	text := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "-"), "+")
+
+	whole, fraction, _ := strings.Cut(text, ".")
+	if (len(whole) == 0 && len(fraction) == 0) || !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("%w: invalid amount %q", ErrValidation, amount)
+	} else if len(fraction) > MoneyDecimals {
+		return 0, fmt.Errorf("%w: amount %q has more than %d decimal places", ErrValidation, amount, MoneyDecimals)
+	}
+
+	units, ok := new(big.Int).SetString(
+		whole+fraction+strings.Repeat("0", MoneyDecimals-len(fraction)), 10,
+	)
+	if !ok || !units.IsInt64() {
+		return 0, fmt.Errorf("%w: %q", ErrMoneyOverflow, amount)
+	}
+	if negative {
+		return -units.Int64(), nil
+	}
+	return units.Int64(), nil
+}
+
+// formatMoneyUnits will format units of 10^-8 with the decimal places (units must already be rounded)
+func formatMoneyUnits(units *big.Int, decimals int) string {
+	sign := ""
+	abs := new(big.Int).Abs(units)
+	if units.Sign() < 0 {
+		sign = "-"
+	}
+	digits := abs.String()
+	if len(digits) <= MoneyDecimals {
+		digits = strings.Repeat("0", MoneyDecimals-len(digits)+1) + digits
+	}
+	whole, fraction := digits[:len(digits)-MoneyDecimals], digits[len(digits)-MoneyDecimals:]
+	if decimals == 0 {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction[:decimals]
+}
+
+// groupThousands will add thousands separators to the whole part of a formatted amount
+func groupThousands(text string) string {
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(text, ".")
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	if hasFraction {
+		return sign + b.String() + "." + fraction
+	}
+	return sign + b.String()
+}
+
+// isDigits will return true if the text is only 0-9 (or empty)
+func isDigits(text string) bool {
+	for _, r := range text {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// currencyFormat is how a currency is displayed
+type currencyFormat struct {
+	decimals int    // Decimal places
+	symbol   string // Prefix symbol (empty uses the currency code as a suffix)
+}
+
+// currencyFormatFor will return the format for the currency (2 decimal places & the code if unknown)
+func currencyFormatFor(currency string) currencyFormat {
+	if format, ok := currencyFormats[currency]; ok {
+		return format
+	}
+	return currencyFormat{decimals: 2}
+}
+
+// BalanceMoney will return the balance in the campaign currency
+func (c *Campaign) BalanceMoney() Money {
+	return MoneyFromFloat(c.Balance, c.Currency)
+}
+
+// BalanceAlertThresholdMoney will return the balance alert threshold in the campaign currency
+func (c *Campaign) BalanceAlertThresholdMoney() Money {
+	return MoneyFromFloat(c.BalanceAlertThreshold, c.Currency)
+}
+
+// PayPerClickRateMoney will return the pay per click rate in the campaign currency
+func (c *Campaign) PayPerClickRateMoney() Money {
+	return MoneyFromFloat(c.PayPerClickRate, c.Currency)
+}
+
+// PayoutRateMoney will return the payout rate in the currency (the currency of the campaign)
+func (g *Goal) PayoutRateMoney(currency string) Money {
+	return MoneyFromFloat(g.PayoutRate, currency)
+}
+
+// AmountMoney will return the amount in the currency (the currency of the campaign)
+func (c *Conversion) AmountMoney(currency string) Money {
+	return MoneyFromFloat(c.Amount, currency)
+}
+
+// CurrencyAmountMoney will return the currency amount in the rate currency
+func (r *Rate) CurrencyAmountMoney() Money {
+	return MoneyFromFloat(r.CurrencyAmount, r.Currency)
+}
+
+// Satoshis will return the price in satoshis
+func (r *Rate) Satoshis() Satoshis {
+	return Satoshis(r.PriceInSatoshis)
+}
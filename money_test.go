@@ -0,0 +1,292 @@
+package tonicpow
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mustParseMoney will parse the amount (failing the test if invalid)
+func mustParseMoney(t *testing.T, amount, currency string) Money {
+	m, err := ParseMoney(amount, currency)
+	assert.NoError(t, err)
+	return m
+}
+
+// TestParseMoney will test the method ParseMoney()
+func TestParseMoney(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid amounts", func(t *testing.T) {
+		tests := []struct {
+			amount string
+			text   string
+		}{
+			{"0", "0"},
+			{"12.34", "12.34"},
+			{"12.340", "12.34"},
+			{"-0.5", "-0.5"},
+			{"+7", "7"},
+			{".25", "0.25"},
+			{"3.", "3"},
+			{"0.00000001", "0.00000001"},
+			{"92233720368.54775807", "92233720368.54775807"},
+		}
+		for _, test := range tests {
+			m, err := ParseMoney(test.amount, "USD")
+			assert.NoError(t, err, test.amount)
+			assert.Equal(t, test.text, m.Text(), test.amount)
+			assert.Equal(t, CurrencyUSD, m.Currency())
+		}
+	})
+
+	t.Run("invalid amounts", func(t *testing.T) {
+		for _, amount := range []string{"", ".", "abc", "1,000", "1.2.3", "1e5", "--1", "0.000000001"} {
+			_, err := ParseMoney(amount, CurrencyUSD)
+			assert.ErrorIs(t, err, ErrValidation, amount)
+		}
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		_, err := ParseMoney("92233720368.54775808", CurrencyUSD)
+		assert.ErrorIs(t, err, ErrMoneyOverflow)
+	})
+}
+
+// TestMoneyFromFloat will test the method MoneyFromFloat()
+func TestMoneyFromFloat(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "0.1", MoneyFromFloat(0.1, CurrencyUSD).Text())
+	assert.Equal(t, "0.3", MoneyFromFloat(0.1+0.2, CurrencyUSD).Text())
+	assert.Equal(t, "120", MoneyFromFloat(120.00, CurrencyUSD).Text())
+	assert.Equal(t, "0.00000001", MoneyFromFloat(0.000000005, CurrencyBSV).Text())
+	assert.Equal(t, "-19.99", MoneyFromFloat(-19.99, CurrencyUSD).Text())
+	assert.True(t, MoneyFromFloat(math.NaN(), CurrencyUSD).IsZero())
+	assert.True(t, MoneyFromFloat(math.Inf(1), CurrencyUSD).IsZero())
+	assert.False(t, MoneyFromFloat(1e300, CurrencyUSD).IsNegative())
+	assert.True(t, MoneyFromFloat(-1e300, CurrencyUSD).IsNegative())
+	assert.Equal(t, 19.99, MoneyFromFloat(19.99, CurrencyUSD).Float64())
+}
+
+// TestMoney_Arithmetic will test the methods Add(), Sub(), Mul(), Neg() and Cmp()
+func TestMoney_Arithmetic(t *testing.T) {
+	t.Parallel()
+
+	t.Run("exact decimal math", func(t *testing.T) {
+		total := Money{}.WithCurrency(CurrencyUSD)
+		for i := 0; i < 10; i++ {
+			var err error
+			total, err = total.Add(mustParseMoney(t, "0.1", CurrencyUSD))
+			assert.NoError(t, err)
+		}
+		assert.True(t, total.Equal(mustParseMoney(t, "1", CurrencyUSD)))
+
+		diff, err := total.Sub(mustParseMoney(t, "1.25", CurrencyUSD))
+		assert.NoError(t, err)
+		assert.Equal(t, "-0.25", diff.Text())
+		assert.True(t, diff.IsNegative())
+		assert.Equal(t, "0.25", diff.Neg().Text())
+
+		var product Money
+		product, err = mustParseMoney(t, "0.015", CurrencyUSD).Mul(3)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.045", product.Text())
+	})
+
+	t.Run("currencies", func(t *testing.T) {
+		usd := mustParseMoney(t, "1", CurrencyUSD)
+		_, err := usd.Add(mustParseMoney(t, "1", CurrencyEUR))
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+		var sum Money
+		sum, err = mustParseMoney(t, "1", "").Add(usd)
+		assert.NoError(t, err)
+		assert.Equal(t, CurrencyUSD, sum.Currency())
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		maxMoney := Money{currency: CurrencyUSD, units: math.MaxInt64}
+		_, err := maxMoney.Add(mustParseMoney(t, "0.00000001", CurrencyUSD))
+		assert.ErrorIs(t, err, ErrMoneyOverflow)
+
+		_, err = maxMoney.Mul(2)
+		assert.ErrorIs(t, err, ErrMoneyOverflow)
+
+		_, err = Money{}.Sub(Money{units: math.MinInt64})
+		assert.ErrorIs(t, err, ErrMoneyOverflow)
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		one := mustParseMoney(t, "1", CurrencyUSD)
+		two := mustParseMoney(t, "2", CurrencyUSD)
+		assert.Equal(t, -1, one.Cmp(two))
+		assert.Equal(t, 1, two.Cmp(one))
+		assert.Equal(t, 0, one.Cmp(one))
+		assert.True(t, Money{}.IsZero())
+	})
+}
+
+// TestMoney_Round will test the method Round()
+func TestMoney_Round(t *testing.T) {
+	t.Parallel()
+
+	m := mustParseMoney(t, "1.005", CurrencyUSD)
+	tests := []struct {
+		decimals int
+		expected string
+		mode     RoundingMode
+	}{
+		{2, "1.01", RoundHalfUp},
+		{2, "1", RoundHalfEven},
+		{2, "1", RoundDown},
+		{2, "1.01", RoundUp},
+		{0, "1", RoundHalfUp},
+		{8, "1.005", RoundDown},
+	}
+	for _, test := range tests {
+		rounded, err := m.Round(test.decimals, test.mode)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, rounded.Text(), "%d %s", test.decimals, test.mode)
+		assert.Equal(t, CurrencyUSD, rounded.Currency())
+	}
+
+	rounded, err := m.Neg().Round(2, RoundHalfUp)
+	assert.NoError(t, err)
+	assert.Equal(t, "-1.01", rounded.Text())
+
+	t.Run("overflow", func(t *testing.T) {
+		maxMoney := Money{currency: CurrencyUSD, units: math.MaxInt64}
+		_, err := maxMoney.Round(2, RoundUp)
+		assert.ErrorIs(t, err, ErrMoneyOverflow)
+		assert.Equal(t, "$92,233,720,368.55", maxMoney.Format())
+
+		rounded, err := maxMoney.Round(2, RoundDown)
+		assert.NoError(t, err)
+		assert.Equal(t, "92233720368.54", rounded.Text())
+	})
+}
+
+// TestMoney_Format will test the method Format()
+func TestMoney_Format(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		amount   string
+		currency string
+		expected string
+	}{
+		{"1234.5", CurrencyUSD, "$1,234.50"},
+		{"1234567.891", CurrencyUSD, "$1,234,567.89"},
+		{"0.005", CurrencyUSD, "$0.01"},
+		{"0.004", CurrencyUSD, "$0.00"},
+		{"-0.005", CurrencyUSD, "-$0.01"},
+		{"10", CurrencyEUR, "€10.00"},
+		{"9.99", CurrencyGBP, "£9.99"},
+		{"1500.5", CurrencyJPY, "¥1,501"},
+		{"0.000042", CurrencyBSV, "0.00004200 BSV"},
+		{"12.5", "xyz", "12.50 XYZ"},
+		{"12.5", "", "12.5"},
+		{"999.999", CurrencyUSD, "$1,000.00"},
+	}
+	for _, test := range tests {
+		m := mustParseMoney(t, test.amount, test.currency)
+		assert.Equal(t, test.expected, m.Format(), test.amount)
+		assert.Equal(t, test.expected, m.String(), test.amount)
+	}
+}
+
+// TestMoney_JSON will test the methods MarshalJSON() and UnmarshalJSON()
+func TestMoney_JSON(t *testing.T) {
+	t.Parallel()
+
+	type payload struct {
+		Amount Money `json:"amount"`
+	}
+
+	t.Run("wire format is a number", func(t *testing.T) {
+		b, err := json.Marshal(payload{Amount: mustParseMoney(t, "19.99", CurrencyUSD)})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"amount":19.99}`, string(b))
+
+		// Compatible with the float64 fields
+		var conversion Conversion
+		assert.NoError(t, json.Unmarshal(b, &conversion))
+		assert.Equal(t, 19.99, conversion.Amount)
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		tests := map[string]string{
+			`{"amount":19.99}`:          "19.99",
+			`{"amount":"0.1"}`:          "0.1",
+			`{"amount":0.123456789}`:    "0.12345679",
+			`{"amount":1e2}`:            "100",
+			`{"amount":null}`:           "0",
+			`{"amount":""}`:             "0",
+			`{"amount":-0.00000001}`:    "-0.00000001",
+			`{"amount":1234567.890123}`: "1234567.890123",
+		}
+		for body, expected := range tests {
+			var p payload
+			assert.NoError(t, json.Unmarshal([]byte(body), &p), body)
+			assert.Equal(t, expected, p.Amount.Text(), body)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var p payload
+		assert.Error(t, json.Unmarshal([]byte(`{"amount":"ten"}`), &p))
+		assert.Error(t, json.Unmarshal([]byte(`{"amount":1e30}`), &p))
+	})
+}
+
+// TestSatoshis will test the Satoshis type
+func TestSatoshis(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "4200 sats", Satoshis(4200).String())
+	assert.Equal(t, "0.000042", Satoshis(4200).BSV().Text())
+	assert.Equal(t, "1.00000000 BSV", SatoshisPerBSV.BSV().Format())
+	assert.Equal(t, CurrencyBSV, Satoshis(1).BSV().Currency())
+}
+
+// TestModel_MoneyAccessors will test the Money accessors of the models
+func TestModel_MoneyAccessors(t *testing.T) {
+	t.Parallel()
+
+	campaign := &Campaign{Balance: 10.1, BalanceAlertThreshold: 2.5, Currency: "USD", PayPerClickRate: 0.015}
+	assert.Equal(t, "$10.10", campaign.BalanceMoney().Format())
+	assert.Equal(t, "2.5", campaign.BalanceAlertThresholdMoney().Text())
+	assert.Equal(t, "0.015", campaign.PayPerClickRateMoney().Text())
+	assert.Equal(t, CurrencyUSD, campaign.PayPerClickRateMoney().Currency())
+
+	goal := &Goal{PayoutRate: 0.25}
+	assert.Equal(t, "€0.25", goal.PayoutRateMoney(CurrencyEUR).Format())
+
+	conversion := &Conversion{Amount: 120}
+	assert.Equal(t, "120", conversion.AmountMoney(CurrencyUSD).Text())
+
+	rate := newTestRate()
+	assert.Equal(t, "0.01", rate.CurrencyAmountMoney().Text())
+	assert.Equal(t, Satoshis(4200), rate.Satoshis())
+}
+
+// TestWithPurchaseAmountMoney will test the method WithPurchaseAmountMoney()
+func TestWithPurchaseAmountMoney(t *testing.T) {
+	t.Parallel()
+
+	options := new(conversionOptions)
+	WithPurchaseAmountMoney(mustParseMoney(t, "1234.56789", CurrencyUSD))(options)
+	assert.Equal(t, "1234.56789", options.payload()[fieldAmount])
+
+	WithPurchaseAmountMoney(mustParseMoney(t, "12345678901.23456789", CurrencyUSD))(options)
+	assert.Equal(t, "12345678901.23456789", options.payload()[fieldAmount])
+
+	WithPurchaseAmount(0.1)(options)
+	assert.Equal(t, "0.1", options.payload()[fieldAmount])
+
+	WithPurchaseAmount(0)(options)
+	assert.NotContains(t, options.payload(), fieldAmount)
+}
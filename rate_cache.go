@@ -0,0 +1,273 @@
+package tonicpow
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateCacheOps allow functional options to be supplied
+// that overwrite default rate cache options.
+type RateCacheOps func(o *rateCacheOptions)
+
+// rateCacheOptions holds all the configuration for the rate cache
+type rateCacheOptions struct {
+	currencyTTL map[string]time.Duration // TTL by currency (lowercase)
+	now         func() time.Time         // Clock (for tests)
+	onError     func(currency string, err error)
+	staleTTL    time.Duration // How long an expired rate is served while refreshing
+	ttl         time.Duration // How long a rate is fresh
+}
+
+// WithRateTTL will set how long a rate is fresh (for all currencies)
+// Default is 1 minute.
+func WithRateTTL(ttl time.Duration) RateCacheOps {
+	return func(o *rateCacheOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithCurrencyTTL will set how long a rate is fresh for a currency (overrides WithRateTTL)
+func WithCurrencyTTL(currency string, ttl time.Duration) RateCacheOps {
+	return func(o *rateCacheOptions) {
+		o.currencyTTL[strings.ToLower(currency)] = ttl
+	}
+}
+
+// WithRateStaleTTL will set how long an expired rate is served (while it is refreshed in the background)
+//
+// If the refresh fails (IE: the API is down) the expired rate keeps being served until the stale TTL ends.
+// Default is 10 minutes, zero disables serving expired rates.
+func WithRateStaleTTL(staleTTL time.Duration) RateCacheOps {
+	return func(o *rateCacheOptions) {
+		o.staleTTL = staleTTL
+	}
+}
+
+// WithRateCacheErrorHandler will set a function that is called when a background refresh fails
+func WithRateCacheErrorHandler(handler func(currency string, err error)) RateCacheOps {
+	return func(o *rateCacheOptions) {
+		o.onError = handler
+	}
+}
+
+// RateCacheStats are the counters of a RateCache
+type RateCacheStats struct {
+	Errors    uint64 // Requests that failed (including background refreshes)
+	Hits      uint64 // Fresh rates served from the cache
+	Misses    uint64 // Rates that were requested (no fresh or stale rate)
+	Refreshes uint64 // Background refreshes of stale rates
+	StaleHits uint64 // Expired rates served from the cache
+}
+
+// rateCacheKey is the key for a cached rate
+type rateCacheKey struct {
+	amount   float64
+	currency string
+}
+
+// rateCacheEntry is a cached rate
+type rateCacheEntry struct {
+	fetchedAt time.Time
+	rate      *Rate
+	response  *StandardResponse
+}
+
+// rateCall is a request in flight (shared by concurrent misses)
+type rateCall struct {
+	done       chan struct{}
+	err        error
+	generation uint64 // Generation of the currency when the request started (see: Invalidate)
+	rate       *Rate
+	response   *StandardResponse
+}
+
+// RateCache is a RateService that caches the rates of another RateService (IE: the Client)
+//
+// Rates are cached by currency & amount. Concurrent misses share one request and
+// expired rates are served while they are refreshed in the background.
+// Cached responses are the response of the request that fetched the rate.
+type RateCache struct {
+	entries     map[rateCacheKey]*rateCacheEntry
+	generations map[string]uint64 // Incremented by Invalidate (by currency)
+	inflight    map[rateCacheKey]*rateCall
+	mu          sync.Mutex
+	options     *rateCacheOptions
+	rates       RateService
+
+	errors    atomic.Uint64
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	refreshes atomic.Uint64
+	staleHits atomic.Uint64
+}
+
+// NewRateCache will return a new cache for the rates
+func NewRateCache(rates RateService, opts ...RateCacheOps) *RateCache {
+	options := &rateCacheOptions{
+		currencyTTL: make(map[string]time.Duration),
+		now:         time.Now,
+		staleTTL:    defaultRateStaleTTL,
+		ttl:         defaultRateTTL,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return &RateCache{
+		entries:     make(map[rateCacheKey]*rateCacheEntry),
+		generations: make(map[string]uint64),
+		inflight:    make(map[rateCacheKey]*rateCall),
+		options:     options,
+		rates:       rates,
+	}
+}
+
+// GetCurrentRate will get a current rate for the given currency (from the cache if fresh)
+func (c *RateCache) GetCurrentRate(currency string,
+	customAmount float64) (rate *Rate, response *StandardResponse, err error) {
+	return c.GetCurrentRateWithContext(context.Background(), currency, customAmount)
+}
+
+// GetCurrentRateWithContext is the same as GetCurrentRate, but uses the given context for the request
+func (c *RateCache) GetCurrentRateWithContext(ctx context.Context, currency string,
+	customAmount float64) (rate *Rate, response *StandardResponse, err error) {
+
+	key := rateCacheKey{amount: customAmount, currency: strings.ToLower(currency)}
+	now := c.options.now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok {
+		age := now.Sub(entry.fetchedAt)
+		ttl := c.ttl(key.currency)
+		if age < ttl {
+			c.hits.Add(1)
+			return copyRate(entry.rate), entry.response, nil
+		} else if age < ttl+c.options.staleTTL {
+			c.staleHits.Add(1)
+			c.refresh(ctx, key, currency, customAmount)
+			return copyRate(entry.rate), entry.response, nil
+		}
+	}
+
+	// Fetch (or wait for the request in flight)
+	c.misses.Add(1)
+	call, _ := c.fetch(ctx, key, currency, customAmount)
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return nil, call.response, call.err
+		}
+		return copyRate(call.rate), call.response, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+// Invalidate will remove the cached rates for the currency
+//
+// Requests in flight for the currency are not cached when they complete
+// (the next call fetches the rate again)
+func (c *RateCache) Invalidate(currency string) {
+	currency = strings.ToLower(currency)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generations[currency]++
+	for key := range c.entries {
+		if key.currency == currency {
+			delete(c.entries, key)
+		}
+	}
+	for key := range c.inflight {
+		if key.currency == currency {
+			delete(c.inflight, key)
+		}
+	}
+}
+
+// Stats will return the counters of the cache
+func (c *RateCache) Stats() RateCacheStats {
+	return RateCacheStats{
+		Errors:    c.errors.Load(),
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Refreshes: c.refreshes.Load(),
+		StaleHits: c.staleHits.Load(),
+	}
+}
+
+// ttl will return the TTL for the currency
+func (c *RateCache) ttl(currency string) time.Duration {
+	if ttl, ok := c.options.currencyTTL[currency]; ok {
+		return ttl
+	}
+	return c.options.ttl
+}
+
+// refresh will fetch the rate in the background (if not already in flight)
+func (c *RateCache) refresh(ctx context.Context, key rateCacheKey, currency string, customAmount float64) {
+	call, started := c.fetch(ctx, key, currency, customAmount)
+	if !started {
+		return
+	}
+	c.refreshes.Add(1)
+	go func() {
+		<-call.done
+		if call.err != nil && c.options.onError != nil {
+			c.options.onError(currency, call.err)
+		}
+	}()
+}
+
+// fetch will start the request for the key, or return the request in flight (started is false)
+//
+// The request is not canceled with the context (other callers may be waiting for it)
+func (c *RateCache) fetch(ctx context.Context, key rateCacheKey, currency string,
+	customAmount float64) (call *rateCall, started bool) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if call = c.inflight[key]; call != nil {
+		return call, false
+	}
+	call = &rateCall{done: make(chan struct{}), generation: c.generations[key.currency]}
+	c.inflight[key] = call
+
+	go func() {
+		call.rate, call.response, call.err = c.rates.GetCurrentRateWithContext(
+			context.WithoutCancel(ctx), currency, customAmount,
+		)
+
+		c.mu.Lock()
+		if c.inflight[key] == call {
+			delete(c.inflight, key)
+		}
+		if call.err == nil && call.rate != nil && call.generation == c.generations[key.currency] {
+			c.entries[key] = &rateCacheEntry{
+				fetchedAt: c.options.now(),
+				rate:      call.rate,
+				response:  call.response,
+			}
+		}
+		c.mu.Unlock()
+
+		if call.err != nil {
+			c.errors.Add(1)
+		}
+		close(call.done)
+	}()
+	return call, true
+}
+
+// copyRate will return a copy of the rate (cached rates are shared)
+func copyRate(rate *Rate) *Rate {
+	if rate == nil {
+		return nil
+	}
+	r := *rate
+	return &r
+}
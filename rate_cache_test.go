@@ -0,0 +1,326 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRateService is a RateService that counts the requests
+type testRateService struct {
+	block    chan struct{} // (optional) requests wait until closed
+	calls    int
+	err      error
+	mu       sync.Mutex
+	satoshis int64
+}
+
+// GetCurrentRate will return a rate
+func (s *testRateService) GetCurrentRate(currency string, amount float64) (*Rate, *StandardResponse, error) {
+	return s.GetCurrentRateWithContext(context.Background(), currency, amount)
+}
+
+// GetCurrentRateWithContext will return a rate (or the error)
+func (s *testRateService) GetCurrentRateWithContext(_ context.Context, currency string,
+	amount float64) (*Rate, *StandardResponse, error) {
+	if s.block != nil {
+		<-s.block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+	return &Rate{Currency: currency, CurrencyAmount: amount, PriceInSatoshis: s.satoshis},
+		&StandardResponse{StatusCode: http.StatusOK}, nil
+}
+
+// setup will set the error and satoshis
+func (s *testRateService) setup(err error, satoshis int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+	s.satoshis = satoshis
+}
+
+// callCount will return the number of requests
+func (s *testRateService) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// TestRateCache will test the RateCache
+func TestRateCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("implements RateService", func(t *testing.T) {
+		var rates RateService = NewRateCache(&testRateService{})
+		assert.NotNil(t, rates)
+	})
+
+	t.Run("hit and miss", func(t *testing.T) {
+		service := &testRateService{satoshis: 4200}
+		cache := NewRateCache(service)
+
+		rate, response, err := cache.GetCurrentRate(testRateCurrency, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4200), rate.PriceInSatoshis)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		rate.PriceInSatoshis = 1 // cached rates are copies
+		rate, _, err = cache.GetCurrentRate("USD", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4200), rate.PriceInSatoshis)
+
+		_, _, err = cache.GetCurrentRate(testRateCurrency, 2)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 2, service.callCount())
+		assert.Equal(t, RateCacheStats{Hits: 1, Misses: 2}, cache.Stats())
+	})
+
+	t.Run("expires after the ttl", func(t *testing.T) {
+		now := time.Now()
+		service := &testRateService{satoshis: 4200}
+		cache := NewRateCache(service, WithRateTTL(time.Minute), WithRateStaleTTL(0))
+		cache.options.now = func() time.Time { return now }
+
+		_, _, err := cache.GetCurrentRate(testRateCurrency, 1)
+		assert.NoError(t, err)
+
+		now = now.Add(time.Minute)
+		service.setup(nil, 5000)
+		var rate *Rate
+		rate, _, err = cache.GetCurrentRate(testRateCurrency, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5000), rate.PriceInSatoshis)
+		assert.Equal(t, uint64(2), cache.Stats().Misses)
+	})
+
+	t.Run("currency ttl", func(t *testing.T) {
+		now := time.Now()
+		service := &testRateService{satoshis: 4200}
+		cache := NewRateCache(service, WithRateTTL(time.Minute), WithCurrencyTTL("EUR", time.Hour), WithRateStaleTTL(0))
+		cache.options.now = func() time.Time { return now }
+
+		_, _, _ = cache.GetCurrentRate("eur", 1)
+		_, _, _ = cache.GetCurrentRate(testRateCurrency, 1)
+		now = now.Add(10 * time.Minute)
+		_, _, _ = cache.GetCurrentRate("eur", 1)
+		_, _, _ = cache.GetCurrentRate(testRateCurrency, 1)
+
+		assert.Equal(t, RateCacheStats{Hits: 1, Misses: 3}, cache.Stats())
+	})
+
+	t.Run("stale while revalidate", func(t *testing.T) {
+		now := time.Now()
+		service := &testRateService{satoshis: 4200}
+		cache := NewRateCache(service, WithRateTTL(time.Minute), WithRateStaleTTL(time.Hour))
+		cache.options.now = func() time.Time { return now }
+
+		_, _, err := cache.GetCurrentRate(testRateCurrency, 1)
+		assert.NoError(t, err)
+
+		// Expired: the stale rate is served and refreshed in the background
+		now = now.Add(2 * time.Minute)
+		service.setup(nil, 5000)
+		var rate *Rate
+		rate, _, err = cache.GetCurrentRate(testRateCurrency, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4200), rate.PriceInSatoshis)
+		assert.Eventually(t, func() bool { return service.callCount() == 2 }, time.Second, time.Millisecond)
+
+		assert.Eventually(t, func() bool {
+			rate, _, err = cache.GetCurrentRate(testRateCurrency, 1)
+			return err == nil && rate.PriceInSatoshis == 5000
+		}, time.Second, time.Millisecond)
+
+		stats := cache.Stats()
+		assert.Equal(t, uint64(1), stats.StaleHits)
+		assert.Equal(t, uint64(1), stats.Refreshes)
+	})
+
+	t.Run("stale on api errors", func(t *testing.T) {
+		now := time.Now()
+		service := &testRateService{satoshis: 4200}
+		var mu sync.Mutex
+		var reported []error
+		cache := NewRateCache(service,
+			WithRateTTL(time.Minute),
+			WithRateStaleTTL(time.Hour),
+			WithRateCacheErrorHandler(func(currency string, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				assert.Equal(t, testRateCurrency, currency)
+				reported = append(reported, err)
+			}),
+		)
+		cache.options.now = func() time.Time { return now }
+
+		_, _, err := cache.GetCurrentRate(testRateCurrency, 1)
+		assert.NoError(t, err)
+
+		now = now.Add(2 * time.Minute)
+		service.setup(errors.New("api is down"), 0)
+		for i := 0; i < 3; i++ {
+			var rate *Rate
+			rate, _, err = cache.GetCurrentRate(testRateCurrency, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(4200), rate.PriceInSatoshis)
+		}
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(reported) > 0
+		}, time.Second, time.Millisecond)
+
+		// Beyond the stale TTL the error is returned
+		now = now.Add(2 * time.Hour)
+		_, _, err = cache.GetCurrentRate(testRateCurrency, 1)
+		assert.Error(t, err)
+		assert.Positive(t, cache.Stats().Errors)
+	})
+
+	t.Run("single flight", func(t *testing.T) {
+		service := &testRateService{block: make(chan struct{}), satoshis: 4200}
+		cache := NewRateCache(service)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rate, _, err := cache.GetCurrentRate(testRateCurrency, 1)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(4200), rate.PriceInSatoshis)
+			}()
+		}
+		assert.Eventually(t, func() bool {
+			return cache.Stats().Misses == 10
+		}, time.Second, time.Millisecond)
+		close(service.block)
+		wg.Wait()
+
+		assert.Equal(t, 1, service.callCount())
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		service := &testRateService{err: errors.New("api is down")}
+		cache := NewRateCache(service)
+
+		rate, _, err := cache.GetCurrentRate(testRateCurrency, 1)
+		assert.Error(t, err)
+		assert.Nil(t, rate)
+
+		service.setup(nil, 4200)
+		rate, _, err = cache.GetCurrentRate(testRateCurrency, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4200), rate.PriceInSatoshis)
+		assert.Equal(t, RateCacheStats{Errors: 1, Misses: 2}, cache.Stats())
+	})
+
+	t.Run("context canceled while waiting", func(t *testing.T) {
+		service := &testRateService{block: make(chan struct{}), satoshis: 4200}
+		cache := NewRateCache(service)
+		defer close(service.block)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		rate, _, err := cache.GetCurrentRateWithContext(ctx, testRateCurrency, 1)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, rate)
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		service := &testRateService{satoshis: 4200}
+		cache := NewRateCache(service)
+
+		_, _, _ = cache.GetCurrentRate(testRateCurrency, 1)
+		_, _, _ = cache.GetCurrentRate(testRateCurrency, 2)
+		_, _, _ = cache.GetCurrentRate("eur", 1)
+		cache.Invalidate("USD")
+		_, _, _ = cache.GetCurrentRate(testRateCurrency, 1)
+		_, _, _ = cache.GetCurrentRate("eur", 1)
+
+		assert.Equal(t, RateCacheStats{Hits: 1, Misses: 4}, cache.Stats())
+	})
+
+	t.Run("invalidate while in flight", func(t *testing.T) {
+		service := &testRateService{block: make(chan struct{}), satoshis: 4200}
+		cache := NewRateCache(service)
+
+		// Start the request (the caller gives up, the request stays in flight)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, _, err := cache.GetCurrentRateWithContext(ctx, testRateCurrency, 1)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		cache.mu.Lock()
+		call := cache.inflight[rateCacheKey{amount: 1, currency: testRateCurrency}]
+		cache.mu.Unlock()
+		assert.NotNil(t, call)
+
+		cache.Invalidate(testRateCurrency)
+		close(service.block)
+		<-call.done
+
+		cache.mu.Lock()
+		assert.Empty(t, cache.entries)
+		assert.Empty(t, cache.inflight)
+		cache.mu.Unlock()
+
+		service.setup(nil, 4300)
+		rate, _, err := cache.GetCurrentRate(testRateCurrency, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4300), rate.PriceInSatoshis)
+		assert.Equal(t, 2, service.callCount())
+	})
+}
+
+// TestRateCache_Client will test the RateCache with the Client
+func TestRateCache_Client(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("cached api rate", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/%s?%s=%f", EnvironmentDevelopment.apiURL,
+			modelRates, testRateCurrency,
+			fieldAmount, 0.0,
+		)
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestRate())
+		assert.NoError(t, err)
+		httpmock.ZeroCallCounters()
+
+		cache := NewRateCache(client)
+		for i := 0; i < 3; i++ {
+			var rate *Rate
+			rate, _, err = cache.GetCurrentRate(testRateCurrency, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, testRateCurrency, rate.Currency)
+		}
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("missing currency", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		var rate *Rate
+		rate, _, err = NewRateCache(client).GetCurrentRate("", 0)
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Nil(t, rate)
+	})
+}
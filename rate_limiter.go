@@ -0,0 +1,157 @@
+package tonicpow
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket rate limiter for outgoing requests (see: WithRateLimit)
+//
+// The limiter adapts to the API: a 429 response (or rate limit headers reporting no
+// remaining requests) pauses all requests until the limit resets.
+// It is safe for concurrent use.
+type RateLimiter struct {
+	burst       int        // Maximum tokens in the bucket
+	lastRefill  time.Time  // Last time the bucket was refilled
+	mu          sync.Mutex // Guards all fields
+	pausedUntil time.Time  // Requests are paused until this time (set from the API)
+	rate        float64    // Tokens added per second (not limited if 0)
+	remaining   int        // Remaining requests reported by the API (-1 if unknown)
+	tokens      float64    // Current tokens in the bucket
+}
+
+// RateLimiterState is a snapshot of the current rate limiter state
+type RateLimiterState struct {
+	Burst       int       // Maximum requests in a burst
+	PausedUntil time.Time // Requests are paused until this time (zero if not paused)
+	Rate        float64   // Requests per second
+	Remaining   int       // Remaining requests reported by the API (-1 if unknown)
+	Tokens      float64   // Requests available without waiting
+}
+
+// NewRateLimiter will return a new token bucket rate limiter
+//
+// If burst is less than 1, it will use a burst of 1. If requestsPerSecond is 0 or less,
+// requests are not limited (they are only paused when the API reports a rate limit)
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	if requestsPerSecond < 0 {
+		requestsPerSecond = 0
+	}
+	return &RateLimiter{
+		burst:      burst,
+		lastRefill: time.Now(),
+		rate:       requestsPerSecond,
+		remaining:  -1,
+		tokens:     float64(burst),
+	}
+}
+
+// Wait will block until a request is allowed or the context is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.refill(now)
+
+		var wait time.Duration
+		if now.Before(l.pausedUntil) {
+			wait = l.pausedUntil.Sub(now)
+		} else if l.rate == 0 {
+			l.mu.Unlock()
+			return nil
+		} else if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		} else {
+			wait = time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		}
+		l.mu.Unlock()
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// State will return a snapshot of the current limiter state
+func (l *RateLimiter) State() RateLimiterState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.refill(now)
+	state := RateLimiterState{
+		Burst:     l.burst,
+		Rate:      l.rate,
+		Remaining: l.remaining,
+		Tokens:    l.tokens,
+	}
+	if now.Before(l.pausedUntil) {
+		state.PausedUntil = l.pausedUntil
+	}
+	return state
+}
+
+// refill will add the tokens earned since the last refill (must hold the lock)
+func (l *RateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.lastRefill); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * l.rate
+		if l.tokens > float64(l.burst) {
+			l.tokens = float64(l.burst)
+		}
+		l.lastRefill = now
+	}
+}
+
+// observe will adapt the limiter using the API response (429 and rate limit headers)
+func (l *RateLimiter) observe(statusCode int, header http.Header) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+
+	// Remaining requests reported by the API
+	if remaining, err := strconv.Atoi(header.Get(headerRateLimitRemaining)); err == nil {
+		l.remaining = remaining
+		if remaining <= 0 {
+			l.pause(now, parseRateLimitReset(header.Get(headerRateLimitReset), now))
+		}
+	}
+
+	// Rate limited: pause until the API allows requests again
+	if statusCode == http.StatusTooManyRequests {
+		wait := parseRetryAfter(header)
+		if wait <= 0 {
+			wait = parseRateLimitReset(header.Get(headerRateLimitReset), now)
+		}
+		if wait <= 0 {
+			wait = defaultRateLimitPause
+		}
+		l.pause(now, wait)
+		l.tokens = 0
+	}
+}
+
+// pause will pause all requests for the duration (must hold the lock)
+func (l *RateLimiter) pause(now time.Time, d time.Duration) {
+	if until := now.Add(d); d > 0 && until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// parseRateLimitReset will parse the rate limit reset header (seconds until reset or unix timestamp)
+func parseRateLimitReset(value string, now time.Time) time.Duration {
+	reset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || reset <= 0 {
+		return 0
+	}
+	if reset > now.Unix()/2 { // Looks like a unix timestamp
+		return time.Unix(reset, 0).Sub(now)
+	}
+	return time.Duration(reset) * time.Second
+}
@@ -0,0 +1,180 @@
+package tonicpow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewRateLimiter will test the method NewRateLimiter()
+func TestNewRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid limiter", func(t *testing.T) {
+		l := NewRateLimiter(10, 5)
+		state := l.State()
+		assert.Equal(t, float64(10), state.Rate)
+		assert.Equal(t, 5, state.Burst)
+		assert.Equal(t, -1, state.Remaining)
+		assert.InDelta(t, 5, state.Tokens, 0.01)
+		assert.True(t, state.PausedUntil.IsZero())
+	})
+
+	t.Run("minimum burst", func(t *testing.T) {
+		l := NewRateLimiter(10, 0)
+		assert.Equal(t, 1, l.State().Burst)
+	})
+
+	t.Run("no rate limit", func(t *testing.T) {
+		l := NewRateLimiter(-1, 1)
+		assert.Equal(t, float64(0), l.State().Rate)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, l.Wait(ctx))
+		}
+
+		// Still paused when the API reports a rate limit
+		l.observe(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"60"}})
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+	})
+}
+
+// TestRateLimiter_Wait will test the method Wait()
+func TestRateLimiter_Wait(t *testing.T) {
+	t.Parallel()
+
+	t.Run("burst then wait", func(t *testing.T) {
+		l := NewRateLimiter(50, 2)
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, l.Wait(context.Background()))
+		}
+		assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		l := NewRateLimiter(0.1, 1)
+		assert.NoError(t, l.Wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		l := NewRateLimiter(1000, 10)
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, l.Wait(context.Background()))
+			}()
+		}
+		wg.Wait()
+		assert.Less(t, l.State().Tokens, float64(10))
+	})
+}
+
+// TestRateLimiter_observe will test the method observe()
+func TestRateLimiter_observe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rate limited with retry after", func(t *testing.T) {
+		l := NewRateLimiter(10, 5)
+		header := http.Header{}
+		header.Set("Retry-After", "2")
+		l.observe(http.StatusTooManyRequests, header)
+
+		state := l.State()
+		assert.WithinDuration(t, time.Now().Add(2*time.Second), state.PausedUntil, 100*time.Millisecond)
+		assert.Less(t, state.Tokens, float64(1))
+	})
+
+	t.Run("rate limited without headers", func(t *testing.T) {
+		l := NewRateLimiter(10, 5)
+		l.observe(http.StatusTooManyRequests, http.Header{})
+		assert.WithinDuration(t, time.Now().Add(defaultRateLimitPause), l.State().PausedUntil, 100*time.Millisecond)
+	})
+
+	t.Run("no remaining requests", func(t *testing.T) {
+		l := NewRateLimiter(10, 5)
+		header := http.Header{}
+		header.Set(headerRateLimitRemaining, "0")
+		header.Set(headerRateLimitReset, strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+		l.observe(http.StatusOK, header)
+
+		state := l.State()
+		assert.Equal(t, 0, state.Remaining)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), state.PausedUntil, 2*time.Second)
+	})
+
+	t.Run("remaining requests", func(t *testing.T) {
+		l := NewRateLimiter(10, 5)
+		header := http.Header{}
+		header.Set(headerRateLimitRemaining, "42")
+		l.observe(http.StatusOK, header)
+
+		state := l.State()
+		assert.Equal(t, 42, state.Remaining)
+		assert.True(t, state.PausedUntil.IsZero())
+	})
+}
+
+// TestParseRateLimitReset will test the method parseRateLimitReset()
+func TestParseRateLimitReset(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	assert.Equal(t, time.Duration(0), parseRateLimitReset("", now))
+	assert.Equal(t, time.Duration(0), parseRateLimitReset("invalid", now))
+	assert.Equal(t, 30*time.Second, parseRateLimitReset("30", now))
+	assert.Equal(t, 45*time.Second, parseRateLimitReset(strconv.FormatInt(now.Unix()+45, 10), time.Unix(now.Unix(), 0)))
+}
+
+// TestClient_RateLimiter will test the method RateLimiter()
+func TestClient_RateLimiter(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("not set", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.Nil(t, client.RateLimiter())
+	})
+
+	t.Run("adapts to the api", func(t *testing.T) {
+		client, err := newTestClient(WithRateLimit(100, 10))
+		assert.NoError(t, err)
+		assert.NotNil(t, client.RateLimiter())
+
+		endpoint := fmt.Sprintf("%s/%s/details/%d", EnvironmentDevelopment.apiURL, modelGoal, testGoalID)
+
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodGet, endpoint, func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusTooManyRequests, `{"message":"slow down"}`)
+			resp.Header.Set("Retry-After", "30")
+			return resp, nil
+		})
+
+		_, _, err = client.GetGoal(testGoalID)
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.False(t, client.RateLimiter().State().PausedUntil.IsZero())
+
+		// The next request waits for the pause (until the context is done)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, _, err = client.GetGoalWithContext(ctx, testGoalID)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,254 @@
+package tonicpow
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// rateCSVHeader is the header of a rates CSV file
+var rateCSVHeader = []string{"currency", "currency_amount", "price_in_satoshis"}
+
+// StaticRateProvider is a RateService with fixed rates (IE: for tests, demos & offline runs)
+//
+// Load rates from a map, or a JSON / CSV file (see: LoadStaticRateProvider)
+type StaticRateProvider struct {
+	mu    sync.RWMutex
+	rates map[string]*Rate
+}
+
+// NewStaticRateProvider will return a provider with the rates (by currency)
+//
+// Nil rates are skipped
+func NewStaticRateProvider(rates map[string]*Rate) *StaticRateProvider {
+	p := &StaticRateProvider{rates: make(map[string]*Rate, len(rates))}
+	for currency, rate := range rates {
+		if rate == nil {
+			continue
+		}
+		r := copyRate(rate)
+		if len(r.Currency) == 0 {
+			r.Currency = currency
+		}
+		p.Set(r)
+	}
+	return p
+}
+
+// LoadStaticRateProvider will return a provider with the rates from a file
+//
+// Files ending in .csv are CSV (currency,currency_amount,price_in_satoshis with a header row),
+// anything else is JSON (an array of rates, as returned by the API)
+func LoadStaticRateProvider(path string) (*StaticRateProvider, error) {
+	file, err := os.Open(path) //nolint:gosec // path is given by the caller
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var rates []*Rate
+	if isCSVPath(path) {
+		rates, err = readRatesCSV(file)
+	} else {
+		err = json.NewDecoder(file).Decode(&rates)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rates from %s: %w", path, err)
+	}
+
+	p := NewStaticRateProvider(nil)
+	for _, rate := range rates {
+		if rate == nil || len(rate.Currency) == 0 {
+			return nil, fmt.Errorf("failed to load rates from %s: %w", path, newMissingAttributeError(fieldCurrency))
+		}
+		p.Set(rate)
+	}
+	return p, nil
+}
+
+// Set will add or replace the rate for its currency
+func (p *StaticRateProvider) Set(rate *Rate) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rates[strings.ToLower(rate.Currency)] = copyRate(rate)
+}
+
+// Rates will return all the rates (sorted by currency)
+func (p *StaticRateProvider) Rates() []*Rate {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	rates := make([]*Rate, 0, len(p.rates))
+	for _, rate := range p.rates {
+		rates = append(rates, copyRate(rate))
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		return strings.ToLower(rates[i].Currency) < strings.ToLower(rates[j].Currency)
+	})
+	return rates
+}
+
+// GetCurrentRate will get the rate for the given currency
+func (p *StaticRateProvider) GetCurrentRate(currency string,
+	customAmount float64) (rate *Rate, response *StandardResponse, err error) {
+	return p.GetCurrentRateWithContext(context.Background(), currency, customAmount)
+}
+
+// GetCurrentRateWithContext is the same as GetCurrentRate (the context is not used)
+//
+// A custom amount scales the price in satoshis of the rate (rounded half up)
+func (p *StaticRateProvider) GetCurrentRateWithContext(_ context.Context, currency string,
+	customAmount float64) (rate *Rate, response *StandardResponse, err error) {
+
+	// Currency is required
+	if len(currency) == 0 {
+		err = newMissingAttributeError(fieldCurrency)
+		return
+	}
+
+	p.mu.RLock()
+	rate = copyRate(p.rates[strings.ToLower(currency)])
+	p.mu.RUnlock()
+	if rate == nil {
+		err = fmt.Errorf("%w: no rate for currency %s", ErrNotFound, currency)
+		return
+	}
+
+	// Scale the rate for the amount
+	if customAmount > 0 {
+		var sats Satoshis
+		if sats, err = rate.ToSatoshis(MoneyFromFloat(customAmount, "")); err != nil {
+			return nil, nil, err
+		}
+		rate.CurrencyAmount = customAmount
+		rate.PriceInSatoshis = int64(sats)
+	}
+
+	response = &StandardResponse{StatusCode: http.StatusOK}
+	response.Body, err = json.Marshal(rate)
+	return
+}
+
+// Save will write the rates to a file (JSON, or CSV if the path ends in .csv)
+//
+// The file is replaced atomically, it can be loaded with LoadStaticRateProvider
+func (p *StaticRateProvider) Save(path string) error {
+	var buf bytes.Buffer
+	rates := p.Rates()
+	if isCSVPath(path) {
+		writer := csv.NewWriter(&buf)
+		_ = writer.Write(rateCSVHeader)
+		for _, rate := range rates {
+			_ = writer.Write([]string{
+				rate.Currency,
+				strconv.FormatFloat(rate.CurrencyAmount, 'f', -1, 64),
+				strconv.FormatInt(rate.PriceInSatoshis, 10),
+			})
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+	} else {
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(rates); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// RecordingRateProvider is a RateService that records the rates of another RateService (IE: the Client)
+//
+// Save the recorded rates to a file and replay them with LoadStaticRateProvider
+type RecordingRateProvider struct {
+	recorded *StaticRateProvider
+	rates    RateService
+}
+
+// NewRecordingRateProvider will return a provider that records the rates
+func NewRecordingRateProvider(rates RateService) *RecordingRateProvider {
+	return &RecordingRateProvider{
+		recorded: NewStaticRateProvider(nil),
+		rates:    rates,
+	}
+}
+
+// GetCurrentRate will get a current rate for the given currency (and record it)
+func (p *RecordingRateProvider) GetCurrentRate(currency string,
+	customAmount float64) (rate *Rate, response *StandardResponse, err error) {
+	return p.GetCurrentRateWithContext(context.Background(), currency, customAmount)
+}
+
+// GetCurrentRateWithContext is the same as GetCurrentRate, but uses the given context for the request
+func (p *RecordingRateProvider) GetCurrentRateWithContext(ctx context.Context, currency string,
+	customAmount float64) (rate *Rate, response *StandardResponse, err error) {
+	if rate, response, err = p.rates.GetCurrentRateWithContext(ctx, currency, customAmount); err == nil && rate != nil {
+		recorded := copyRate(rate)
+		if len(recorded.Currency) == 0 {
+			recorded.Currency = currency
+		}
+		p.recorded.Set(recorded)
+	}
+	return
+}
+
+// Recorded will return a provider with the recorded rates (a snapshot)
+func (p *RecordingRateProvider) Recorded() *StaticRateProvider {
+	return NewStaticRateProvider(ratesByCurrency(p.recorded.Rates()))
+}
+
+// Save will write the recorded rates to a file (JSON, or CSV if the path ends in .csv)
+func (p *RecordingRateProvider) Save(path string) error {
+	return p.recorded.Save(path)
+}
+
+// readRatesCSV will read the rates from CSV (with a header row)
+func readRatesCSV(r io.Reader) (rates []*Rate, err error) {
+	var records [][]string
+	if records, err = csv.NewReader(r).ReadAll(); err != nil {
+		return
+	}
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), rateCSVHeader[0]) {
+			continue
+		} else if len(record) != len(rateCSVHeader) {
+			return nil, fmt.Errorf("line %d: expected %d fields", i+1, len(rateCSVHeader))
+		}
+		rate := &Rate{Currency: strings.TrimSpace(record[0])}
+		if rate.CurrencyAmount, err = strconv.ParseFloat(strings.TrimSpace(record[1]), 64); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if rate.PriceInSatoshis, err = strconv.ParseInt(strings.TrimSpace(record[2]), 10, 64); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		rates = append(rates, rate)
+	}
+	return
+}
+
+// ratesByCurrency will return the rates keyed by currency
+func ratesByCurrency(rates []*Rate) map[string]*Rate {
+	m := make(map[string]*Rate, len(rates))
+	for _, rate := range rates {
+		m[strings.ToLower(rate.Currency)] = rate
+	}
+	return m
+}
+
+// isCSVPath will return true if the file is CSV (by extension)
+func isCSVPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".csv")
+}
@@ -0,0 +1,215 @@
+package tonicpow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStaticRateProvider will test the StaticRateProvider
+func TestStaticRateProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("implements RateService", func(t *testing.T) {
+		var rates RateService = NewStaticRateProvider(nil)
+		assert.NotNil(t, rates)
+	})
+
+	t.Run("rates from a map", func(t *testing.T) {
+		p := NewStaticRateProvider(map[string]*Rate{
+			"USD":       {CurrencyAmount: 0.01, PriceInSatoshis: 4200},
+			CurrencyEUR: {Currency: CurrencyEUR, CurrencyAmount: 1, PriceInSatoshis: 500000},
+		})
+
+		rate, response, err := p.GetCurrentRate(testRateCurrency, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "USD", rate.Currency)
+		assert.Equal(t, int64(4200), rate.PriceInSatoshis)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		var decoded Rate
+		assert.NoError(t, json.Unmarshal(response.Body, &decoded))
+		assert.Equal(t, *rate, decoded)
+
+		// Rates are copies
+		rate.PriceInSatoshis = 1
+		rate, _, err = p.GetCurrentRateWithContext(context.Background(), "usd", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4200), rate.PriceInSatoshis)
+
+		assert.Len(t, p.Rates(), 2)
+		assert.Equal(t, CurrencyEUR, p.Rates()[0].Currency)
+	})
+
+	t.Run("nil rates are skipped", func(t *testing.T) {
+		p := NewStaticRateProvider(map[string]*Rate{
+			CurrencyEUR: nil,
+			CurrencyUSD: {CurrencyAmount: 0.01, PriceInSatoshis: 4200},
+		})
+		assert.Len(t, p.Rates(), 1)
+
+		_, _, err := p.GetCurrentRate(CurrencyEUR, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("custom amount", func(t *testing.T) {
+		p := NewStaticRateProvider(map[string]*Rate{
+			CurrencyUSD: {CurrencyAmount: 0.01, PriceInSatoshis: 4200},
+		})
+		rate, _, err := p.GetCurrentRate(testRateCurrency, 2.5)
+		assert.NoError(t, err)
+		assert.Equal(t, 2.5, rate.CurrencyAmount)
+		assert.Equal(t, int64(1050000), rate.PriceInSatoshis)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		p := NewStaticRateProvider(map[string]*Rate{CurrencyUSD: {}})
+
+		_, _, err := p.GetCurrentRate("", 0)
+		assert.ErrorIs(t, err, ErrValidation)
+
+		_, _, err = p.GetCurrentRate("xyz", 0)
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		_, _, err = p.GetCurrentRate(CurrencyUSD, 1)
+		assert.ErrorIs(t, err, ErrInvalidRate)
+	})
+
+	t.Run("save and load json", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "rates.json")
+		p := NewStaticRateProvider(map[string]*Rate{
+			CurrencyUSD: {CurrencyAmount: 0.01, PriceInSatoshis: 4200},
+			CurrencyGBP: {CurrencyAmount: 1, PriceInSatoshis: 530000},
+		})
+		assert.NoError(t, p.Save(path))
+
+		loaded, err := LoadStaticRateProvider(path)
+		assert.NoError(t, err)
+		assert.Equal(t, p.Rates(), loaded.Rates())
+	})
+
+	t.Run("save and load csv", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "rates.CSV")
+		p := NewStaticRateProvider(map[string]*Rate{
+			CurrencyUSD: {CurrencyAmount: 0.01, PriceInSatoshis: 4200},
+		})
+		assert.NoError(t, p.Save(path))
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "currency,currency_amount,price_in_satoshis\nusd,0.01,4200\n", string(data))
+
+		var loaded *StaticRateProvider
+		loaded, err = LoadStaticRateProvider(path)
+		assert.NoError(t, err)
+		assert.Equal(t, p.Rates(), loaded.Rates())
+	})
+
+	t.Run("load csv without a header", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "rates.csv")
+		assert.NoError(t, os.WriteFile(path, []byte("usd, 0.01, 4200\neur,1,500000\n"), 0o600))
+
+		p, err := LoadStaticRateProvider(path)
+		assert.NoError(t, err)
+		assert.Len(t, p.Rates(), 2)
+
+		var rate *Rate
+		rate, _, err = p.GetCurrentRate(CurrencyUSD, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4200), rate.PriceInSatoshis)
+	})
+
+	t.Run("load errors", func(t *testing.T) {
+		dir := t.TempDir()
+		files := map[string]string{
+			"bad.json":       "{not json",
+			"missing.json":   `[{"currency_amount":1,"price_in_satoshis":1}]`,
+			"fields.csv":     "usd,0.01\n",
+			"amount.csv":     "usd,cheap,4200\n",
+			"satoshis.csv":   "usd,0.01,many\n",
+			"null_rate.json": "[null]",
+		}
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+			_, err := LoadStaticRateProvider(path)
+			assert.Error(t, err, name)
+		}
+
+		_, err := LoadStaticRateProvider(filepath.Join(dir, "does-not-exist.json"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+// TestRecordingRateProvider will test the RecordingRateProvider
+func TestRecordingRateProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("record and replay", func(t *testing.T) {
+		live := newTestRates()
+		recorder := NewRecordingRateProvider(live)
+
+		for _, currency := range []string{CurrencyUSD, CurrencyEUR, CurrencyUSD} {
+			rate, _, err := recorder.GetCurrentRate(currency, 0)
+			assert.NoError(t, err)
+			assert.NotNil(t, rate)
+		}
+		_, _, err := recorder.GetCurrentRate("xyz", 0)
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		path := filepath.Join(t.TempDir(), "rates.json")
+		assert.NoError(t, recorder.Save(path))
+
+		var replay *StaticRateProvider
+		replay, err = LoadStaticRateProvider(path)
+		assert.NoError(t, err)
+		assert.Len(t, replay.Rates(), 2)
+
+		for currency, expected := range live.rates {
+			var rate *Rate
+			rate, _, err = replay.GetCurrentRate(currency, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, rate)
+		}
+		assert.Equal(t, replay.Rates(), recorder.Recorded().Rates())
+	})
+
+	t.Run("save error", func(t *testing.T) {
+		recorder := NewRecordingRateProvider(newTestRates())
+		err := recorder.Save(filepath.Join(t.TempDir(), "missing", "rates.json"))
+		assert.Error(t, err)
+	})
+}
+
+// TestRecordingRateProvider_Client will test recording the rates of the Client
+func TestRecordingRateProvider_Client(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	client, err := newTestClient()
+	assert.NoError(t, err)
+	assert.NotNil(t, client)
+
+	endpoint := fmt.Sprintf(
+		"%s/%s/%s?%s=%f", EnvironmentDevelopment.apiURL,
+		modelRates, testRateCurrency,
+		fieldAmount, 0.0,
+	)
+	err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestRate())
+	assert.NoError(t, err)
+
+	recorder := NewRecordingRateProvider(client)
+	var rate *Rate
+	rate, _, err = recorder.GetCurrentRate(testRateCurrency, 0)
+	assert.NoError(t, err)
+
+	var replayed *Rate
+	replayed, _, err = recorder.Recorded().GetCurrentRate(testRateCurrency, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, rate, replayed)
+}
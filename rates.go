@@ -1,6 +1,7 @@
 package tonicpow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,16 +12,23 @@ import (
 // For more information: https://docs.tonicpow.com/#71b8b7fc-317a-4e68-bd2a-5b0da012361c
 func (c *Client) GetCurrentRate(currency string,
 	customAmount float64) (rate *Rate, response *StandardResponse, err error) {
+	return c.GetCurrentRateWithContext(context.Background(), currency, customAmount)
+}
+
+// GetCurrentRateWithContext is the same as GetCurrentRate, but uses the given context for the request
+func (c *Client) GetCurrentRateWithContext(ctx context.Context, currency string,
+	customAmount float64) (rate *Rate, response *StandardResponse, err error) {
 
 	// Currency is required
 	if len(currency) == 0 {
-		err = fmt.Errorf("missing required attribute: %s", fieldCurrency)
+		err = newMissingAttributeError(fieldCurrency)
 		return
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
+	ctx = withOperation(ctx, Operation{Name: "GetCurrentRate"})
+	if response, err = c.RequestWithContext(
+		ctx, http.MethodGet,
 		fmt.Sprintf("/%s/%s?%s=%f", modelRates, currency, fieldAmount, customAmount),
 		nil, http.StatusOK,
 	); err != nil {
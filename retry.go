@@ -0,0 +1,162 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy is the configuration for retrying failed requests (see: WithRetryPolicy)
+//
+// Requests using a retryable method (GET, PUT, DELETE by default) or sent with an idempotency key
+// are retried on network errors and retryable status codes. Other requests (POST) are only retried
+// when it is safe: the API rejected the request due to rate limiting (429) or the connection was never made.
+type RetryPolicy struct {
+	BaseDelay            time.Duration           // Delay before the first retry (doubles on each attempt)
+	Jitter               float64                 // Randomize each delay by +/- this fraction (0.0 - 1.0)
+	MaxAttempts          int                     // Maximum number of attempts (including the first request)
+	MaxDelay             time.Duration           // Maximum delay between attempts (also caps Retry-After)
+	OnRetry              func(attempt RetryInfo) // (optional) Hook that is fired before each retry
+	RetryableMethods     []string                // HTTP methods that can always be retried
+	RetryableStatusCodes []int                   // HTTP status codes that trigger a retry
+}
+
+// RetryInfo is the information about a retry attempt (passed to RetryPolicy.OnRetry)
+type RetryInfo struct {
+	Attempt    int           // The attempt that failed (1 = first request)
+	Delay      time.Duration // Delay before the next attempt
+	Endpoint   string        // Request endpoint
+	Err        error         // Error from the failed attempt
+	Method     string        // HTTP method
+	StatusCode int           // Status code from the failed attempt (0 if there was no response)
+}
+
+// DefaultRetryPolicy will return a RetryPolicy with the default settings
+//
+// Useful for starting with the default and then modifying as needed
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		BaseDelay:   defaultRetryBaseDelay,
+		Jitter:      defaultRetryJitter,
+		MaxAttempts: defaultRetryCount + 1,
+		MaxDelay:    defaultRetryMaxDelay,
+		RetryableMethods: []string{
+			http.MethodDelete,
+			http.MethodGet,
+			http.MethodPut,
+		},
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// shouldRetry will return true if the failed attempt can be retried
+//
+// idempotent is true if the request can safely be sent again (retryable method or idempotency key)
+func (p *RetryPolicy) shouldRetry(idempotent bool, attempt int, response *StandardResponse, err error) bool {
+
+	// Success, out of attempts or the caller is done waiting
+	if err == nil || attempt >= p.MaxAttempts ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	// No response (network error)
+	if response == nil {
+		return idempotent || isDialError(err)
+	}
+
+	// Is the status code retryable?
+	if !p.isRetryableStatus(response.StatusCode) {
+		return false
+	}
+	return idempotent || response.StatusCode == http.StatusTooManyRequests
+}
+
+// isRetryableMethod will return true if the HTTP method is in the list of retryable methods
+func (p *RetryPolicy) isRetryableMethod(httpMethod string) bool {
+	return isInList(strings.ToUpper(httpMethod), p.RetryableMethods)
+}
+
+// isRetryableStatus will return true if the status code is in the list of retryable codes
+func (p *RetryPolicy) isRetryableStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// delay will return the delay before the next attempt (exponential backoff with jitter)
+func (p *RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+
+	// Retry-After from the API takes precedence
+	if retryAfter > 0 {
+		if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+			return p.MaxDelay
+		}
+		return retryAfter
+	}
+
+	// Exponential backoff (base * 2^(attempt-1))
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	// Add jitter
+	if p.Jitter > 0 && d > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(d)) //nolint:gosec // jitter does not need crypto/rand
+	}
+	return d
+}
+
+// parseRetryAfter will parse the Retry-After header (seconds or HTTP date)
+func parseRetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if len(value) == 0 {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// isDialError will return true if the connection was never established (request was not sent)
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// sleepContext will wait for the duration or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
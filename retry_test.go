@@ -0,0 +1,226 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRetryPolicy will return a retry policy without delays for testing
+func newTestRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.Jitter = 0
+	return policy
+}
+
+// TestDefaultRetryPolicy will test the method DefaultRetryPolicy()
+func TestDefaultRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := DefaultRetryPolicy()
+	assert.Equal(t, defaultRetryCount+1, policy.MaxAttempts)
+	assert.Equal(t, defaultRetryBaseDelay, policy.BaseDelay)
+	assert.Equal(t, defaultRetryMaxDelay, policy.MaxDelay)
+	assert.Equal(t, defaultRetryJitter, policy.Jitter)
+	assert.Contains(t, policy.RetryableMethods, http.MethodGet)
+	assert.NotContains(t, policy.RetryableMethods, http.MethodPost)
+	assert.Contains(t, policy.RetryableStatusCodes, http.StatusTooManyRequests)
+	assert.Nil(t, policy.OnRetry)
+}
+
+// TestRetryPolicy_shouldRetry will test the method shouldRetry()
+func TestRetryPolicy_shouldRetry(t *testing.T) {
+	t.Parallel()
+
+	policy := newTestRetryPolicy()
+	apiErr := errors.New("api error")
+	dialErr := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	readErr := &net.OpError{Op: "read", Err: errors.New("connection reset")}
+
+	tests := []struct {
+		name     string
+		method   string
+		key      string
+		attempt  int
+		response *StandardResponse
+		err      error
+		expected bool
+	}{
+		{"no error", http.MethodGet, "", 1, &StandardResponse{StatusCode: http.StatusOK}, nil, false},
+		{"get 503", http.MethodGet, "", 1, &StandardResponse{StatusCode: http.StatusServiceUnavailable}, apiErr, true},
+		{"get 404", http.MethodGet, "", 1, &StandardResponse{StatusCode: http.StatusNotFound}, apiErr, false},
+		{"get out of attempts", http.MethodGet, "", 3, &StandardResponse{StatusCode: http.StatusServiceUnavailable}, apiErr, false},
+		{"get network error", http.MethodGet, "", 1, nil, readErr, true},
+		{"get context canceled", http.MethodGet, "", 1, nil, context.Canceled, false},
+		{"post 503", http.MethodPost, "", 1, &StandardResponse{StatusCode: http.StatusServiceUnavailable}, apiErr, false},
+		{"post 429", http.MethodPost, "", 1, &StandardResponse{StatusCode: http.StatusTooManyRequests}, apiErr, true},
+		{"post dial error", http.MethodPost, "", 1, nil, dialErr, true},
+		{"post read error", http.MethodPost, "", 1, nil, readErr, false},
+		{"post 503 with idempotency key", http.MethodPost, "key", 1, &StandardResponse{StatusCode: http.StatusServiceUnavailable}, apiErr, true},
+		{"post read error with idempotency key", http.MethodPost, "key", 1, nil, readErr, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			idempotent := policy.isRetryableMethod(test.method) || len(test.key) > 0
+			assert.Equal(t, test.expected, policy.shouldRetry(idempotent, test.attempt, test.response, test.err))
+		})
+	}
+}
+
+// TestRetryPolicy_delay will test the method delay()
+func TestRetryPolicy_delay(t *testing.T) {
+	t.Parallel()
+
+	t.Run("exponential backoff", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+		assert.Equal(t, 100*time.Millisecond, policy.delay(1, 0))
+		assert.Equal(t, 200*time.Millisecond, policy.delay(2, 0))
+		assert.Equal(t, 400*time.Millisecond, policy.delay(3, 0))
+		assert.Equal(t, time.Second, policy.delay(10, 0))
+	})
+
+	t.Run("retry after", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 5 * time.Second}
+		assert.Equal(t, 2*time.Second, policy.delay(1, 2*time.Second))
+		assert.Equal(t, 5*time.Second, policy.delay(1, time.Minute))
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, Jitter: 0.5}
+		for i := 0; i < 100; i++ {
+			d := policy.delay(1, 0)
+			assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+			assert.LessOrEqual(t, d, 150*time.Millisecond)
+		}
+	})
+}
+
+// TestParseRetryAfter will test the method parseRetryAfter()
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{}
+	assert.Equal(t, time.Duration(0), parseRetryAfter(header))
+
+	header.Set("Retry-After", "3")
+	assert.Equal(t, 3*time.Second, parseRetryAfter(header))
+
+	header.Set("Retry-After", "invalid")
+	assert.Equal(t, time.Duration(0), parseRetryAfter(header))
+
+	header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	d := parseRetryAfter(header)
+	assert.Greater(t, d, 50*time.Second)
+	assert.LessOrEqual(t, d, time.Minute)
+}
+
+// TestClient_Request_RetryPolicy will test retrying requests using the RetryPolicy
+func TestClient_Request_RetryPolicy(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("get is retried until success", func(t *testing.T) {
+		var retries []RetryInfo
+		policy := newTestRetryPolicy()
+		policy.OnRetry = func(attempt RetryInfo) {
+			retries = append(retries, attempt)
+		}
+
+		client, err := newTestClient(WithRetryPolicy(policy))
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		endpoint := fmt.Sprintf("%s/%s/details/%d", EnvironmentDevelopment.apiURL, modelGoal, testGoalID)
+
+		calls := 0
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodGet, endpoint, func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				return httpmock.NewStringResponse(http.StatusServiceUnavailable, `{"message":"unavailable"}`), nil
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, newTestGoal())
+		})
+
+		var goal *Goal
+		goal, _, err = client.GetGoal(testGoalID)
+		assert.NoError(t, err)
+		assert.NotNil(t, goal)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, 1, len(retries))
+		assert.Equal(t, 1, retries[0].Attempt)
+		assert.Equal(t, http.MethodGet, retries[0].Method)
+		assert.Equal(t, http.StatusServiceUnavailable, retries[0].StatusCode)
+	})
+
+	t.Run("post is not retried on server error", func(t *testing.T) {
+		client, err := newTestClient(WithRetryPolicy(newTestRetryPolicy()))
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelConversion)
+
+		calls := 0
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodPost, endpoint, func(req *http.Request) (*http.Response, error) {
+			calls++
+			return httpmock.NewStringResponse(http.StatusBadGateway, `{"message":"bad gateway"}`), nil
+		})
+
+		_, _, err = client.CreateConversion(WithGoalID(testGoalID), WithTncpwSession(testTncpwSession))
+		assert.ErrorIs(t, err, ErrServerError)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("post is retried when rate limited", func(t *testing.T) {
+		client, err := newTestClient(WithRetryPolicy(newTestRetryPolicy()))
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelConversion)
+
+		calls := 0
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodPost, endpoint, func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				resp := httpmock.NewStringResponse(http.StatusTooManyRequests, `{"message":"slow down"}`)
+				resp.Header.Set("Retry-After", "0")
+				return resp, nil
+			}
+			return httpmock.NewJsonResponse(http.StatusCreated, newTestConversion())
+		})
+
+		var conversion *Conversion
+		conversion, _, err = client.CreateConversion(WithGoalID(testGoalID), WithTncpwSession(testTncpwSession))
+		assert.NoError(t, err)
+		assert.NotNil(t, conversion)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("context canceled while waiting", func(t *testing.T) {
+		policy := newTestRetryPolicy()
+		policy.BaseDelay = time.Minute
+		policy.MaxDelay = time.Minute
+
+		client, err := newTestClient(WithRetryPolicy(policy))
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		endpoint := fmt.Sprintf("%s/%s/details/%d", EnvironmentDevelopment.apiURL, modelGoal, testGoalID)
+		mockResponseFeed(endpoint, http.StatusServiceUnavailable, `{"message":"unavailable"}`)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, _, err = client.GetGoalWithContext(ctx, testGoalID)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,182 @@
+package tonicpow
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// sessionContextKey is the context key for the tncpw_session
+type sessionContextKey struct{}
+
+// SessionOps allow functional options to be supplied
+// that overwrite default session middleware options.
+type SessionOps func(o *sessionOptions)
+
+// sessionOptions holds all the configuration for the session middleware
+type sessionOptions struct {
+	cookieDomain string        // Cookie domain (empty is the current host)
+	cookieName   string        // Cookie name
+	cookiePath   string        // Cookie path
+	maxAge       time.Duration // How long the session is kept
+	queryParam   string        // Landing page query parameter
+	sameSite     http.SameSite // Cookie SameSite mode
+	secure       *bool         // Secure cookie (nil detects HTTPS)
+}
+
+// WithSessionCookieName will set the name of the first-party cookie
+// Default is tncpw_session.
+func WithSessionCookieName(name string) SessionOps {
+	return func(o *sessionOptions) {
+		o.cookieName = name
+	}
+}
+
+// WithSessionCookieDomain will set the domain of the cookie (IE: example.com to share with subdomains)
+// Default is the current host.
+func WithSessionCookieDomain(domain string) SessionOps {
+	return func(o *sessionOptions) {
+		o.cookieDomain = domain
+	}
+}
+
+// WithSessionCookiePath will set the path of the cookie
+// Default is /.
+func WithSessionCookiePath(path string) SessionOps {
+	return func(o *sessionOptions) {
+		o.cookiePath = path
+	}
+}
+
+// WithSessionMaxAge will set how long the session cookie is kept
+// Default is 30 days.
+func WithSessionMaxAge(maxAge time.Duration) SessionOps {
+	return func(o *sessionOptions) {
+		o.maxAge = maxAge
+	}
+}
+
+// WithSessionQueryParam will set the landing page query parameter
+// Default is tncpw_session.
+func WithSessionQueryParam(param string) SessionOps {
+	return func(o *sessionOptions) {
+		o.queryParam = param
+	}
+}
+
+// WithSessionSameSite will set the SameSite mode of the cookie
+// Default is http.SameSiteLaxMode.
+func WithSessionSameSite(sameSite http.SameSite) SessionOps {
+	return func(o *sessionOptions) {
+		o.sameSite = sameSite
+	}
+}
+
+// WithSessionSecure will set the Secure flag of the cookie
+// Default is Secure when the request is HTTPS (including X-Forwarded-Proto: https).
+func WithSessionSecure(secure bool) SessionOps {
+	return func(o *sessionOptions) {
+		o.secure = &secure
+	}
+}
+
+// SessionMiddleware will return net/http middleware that captures the tncpw_session
+//
+// On landing (IE: https://example.com/?tncpw_session=...) the session is saved in a
+// first-party cookie, on later requests the session is read from the cookie. The
+// session is added to the request context (see: SessionFromContext).
+func SessionMiddleware(opts ...SessionOps) func(next http.Handler) http.Handler {
+	options := &sessionOptions{
+		cookieName: fieldVisitorSessionGUID,
+		cookiePath: "/",
+		maxAge:     defaultSessionMaxAge,
+		queryParam: fieldVisitorSessionGUID,
+		sameSite:   http.SameSiteLaxMode,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var current string
+			if cookie, err := r.Cookie(options.cookieName); err == nil && isValidSession(cookie.Value) {
+				current = cookie.Value
+			}
+
+			session := current
+			if landing := r.URL.Query().Get(options.queryParam); isValidSession(landing) {
+				session = landing
+			}
+
+			if len(session) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Save (or refresh) the session cookie on landing
+			if session != current {
+				http.SetCookie(w, options.cookie(r, session))
+			}
+
+			next.ServeHTTP(w, r.WithContext(ContextWithSession(r.Context(), session)))
+		})
+	}
+}
+
+// cookie will return the session cookie for the request
+func (o *sessionOptions) cookie(r *http.Request, session string) *http.Cookie {
+	secure := r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+	if o.secure != nil {
+		secure = *o.secure
+	}
+	return &http.Cookie{
+		Domain:   o.cookieDomain,
+		Expires:  time.Now().Add(o.maxAge).UTC(),
+		HttpOnly: true,
+		MaxAge:   int(o.maxAge.Seconds()),
+		Name:     o.cookieName,
+		Path:     o.cookiePath,
+		SameSite: o.sameSite,
+		Secure:   secure,
+		Value:    session,
+	}
+}
+
+// ContextWithSession will return a context with the tncpw_session
+func ContextWithSession(ctx context.Context, session string) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, session)
+}
+
+// SessionFromContext will return the tncpw_session from the context (if set)
+func SessionFromContext(ctx context.Context) string {
+	session, _ := ctx.Value(sessionContextKey{}).(string)
+	return session
+}
+
+// CreateConversionForRequest will fire a conversion for the tncpw_session of the request (see: SessionMiddleware)
+//
+// The request context is used, if the request has no session the other options must
+// identify the visitor (IE: WithUserID), otherwise a ValidationError is returned
+func (c *Client) CreateConversionForRequest(r *http.Request, opts ...ConversionOps) (conversion *Conversion,
+	response *StandardResponse, err error) {
+	if session := SessionFromContext(r.Context()); len(session) > 0 {
+		opts = append([]ConversionOps{WithTncpwSession(session)}, opts...)
+	}
+	return c.CreateConversionWithContext(r.Context(), opts...)
+}
+
+// isValidSession will return true if the value looks like a session (letters, numbers, "_" or "-")
+func isValidSession(session string) bool {
+	if len(session) == 0 || len(session) > maxSessionLength {
+		return false
+	}
+	for _, r := range session {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
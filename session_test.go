@@ -0,0 +1,171 @@
+package tonicpow
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveSession will serve the request through the middleware and return the recorder and the captured session
+func serveSession(r *http.Request, opts ...SessionOps) (*httptest.ResponseRecorder, string) {
+	var session string
+	handler := SessionMiddleware(opts...)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		session = SessionFromContext(r.Context())
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w, session
+}
+
+// TestSessionMiddleware will test the method SessionMiddleware()
+func TestSessionMiddleware(t *testing.T) {
+	t.Parallel()
+
+	t.Run("capture on landing", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/product?tncpw_session="+testTncpwSession, nil)
+		w, session := serveSession(r)
+		assert.Equal(t, testTncpwSession, session)
+
+		cookies := w.Result().Cookies()
+		assert.Len(t, cookies, 1)
+		assert.Equal(t, fieldVisitorSessionGUID, cookies[0].Name)
+		assert.Equal(t, testTncpwSession, cookies[0].Value)
+		assert.Equal(t, "/", cookies[0].Path)
+		assert.Equal(t, int(defaultSessionMaxAge.Seconds()), cookies[0].MaxAge)
+		assert.True(t, cookies[0].HttpOnly)
+		assert.False(t, cookies[0].Secure)
+		assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+	})
+
+	t.Run("read from cookie", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/checkout", nil)
+		r.AddCookie(&http.Cookie{Name: fieldVisitorSessionGUID, Value: testTncpwSession})
+		w, session := serveSession(r)
+		assert.Equal(t, testTncpwSession, session)
+		assert.Empty(t, w.Result().Cookies())
+	})
+
+	t.Run("landing replaces the cookie", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/?tncpw_session=NewSession123", nil)
+		r.AddCookie(&http.Cookie{Name: fieldVisitorSessionGUID, Value: testTncpwSession})
+		w, session := serveSession(r)
+		assert.Equal(t, "NewSession123", session)
+		assert.Len(t, w.Result().Cookies(), 1)
+	})
+
+	t.Run("no session", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w, session := serveSession(r)
+		assert.Equal(t, "", session)
+		assert.Empty(t, w.Result().Cookies())
+	})
+
+	t.Run("invalid session is ignored", func(t *testing.T) {
+		for _, value := range []string{"bad%20session", "<script>", strings.Repeat("a", maxSessionLength+1)} {
+			r := httptest.NewRequest(http.MethodGet, "/?tncpw_session="+value, nil)
+			w, session := serveSession(r)
+			assert.Equal(t, "", session, value)
+			assert.Empty(t, w.Result().Cookies())
+		}
+	})
+
+	t.Run("custom options", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/?ref="+testTncpwSession, nil)
+		w, session := serveSession(r,
+			WithSessionCookieName("_tp"),
+			WithSessionCookieDomain("example.com"),
+			WithSessionCookiePath("/shop"),
+			WithSessionMaxAge(time.Hour),
+			WithSessionQueryParam("ref"),
+			WithSessionSameSite(http.SameSiteStrictMode),
+			WithSessionSecure(true),
+		)
+		assert.Equal(t, testTncpwSession, session)
+
+		cookies := w.Result().Cookies()
+		assert.Len(t, cookies, 1)
+		assert.Equal(t, "_tp", cookies[0].Name)
+		assert.Equal(t, "example.com", cookies[0].Domain)
+		assert.Equal(t, "/shop", cookies[0].Path)
+		assert.Equal(t, 3600, cookies[0].MaxAge)
+		assert.Equal(t, http.SameSiteStrictMode, cookies[0].SameSite)
+		assert.True(t, cookies[0].Secure)
+	})
+
+	t.Run("secure on https", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/?tncpw_session="+testTncpwSession, nil)
+		r.TLS = &tls.ConnectionState{}
+		w, _ := serveSession(r)
+		assert.True(t, w.Result().Cookies()[0].Secure)
+
+		r = httptest.NewRequest(http.MethodGet, "/?tncpw_session="+testTncpwSession, nil)
+		r.Header.Set("X-Forwarded-Proto", "https")
+		w, _ = serveSession(r)
+		assert.True(t, w.Result().Cookies()[0].Secure)
+	})
+}
+
+// TestSessionFromContext will test the method SessionFromContext()
+func TestSessionFromContext(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", SessionFromContext(context.Background()))
+	assert.Equal(t, testTncpwSession, SessionFromContext(ContextWithSession(context.Background(), testTncpwSession)))
+}
+
+// TestClient_CreateConversionForRequest will test the method CreateConversionForRequest()
+func TestClient_CreateConversionForRequest(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelConversion)
+
+	t.Run("conversion for the session", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		var sent map[string]string
+		httpmock.RegisterResponder(http.MethodPost, endpoint, func(req *http.Request) (*http.Response, error) {
+			if decodeErr := json.NewDecoder(req.Body).Decode(&sent); decodeErr != nil {
+				return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+			}
+			return httpmock.NewJsonResponse(http.StatusCreated, newTestConversion())
+		})
+
+		r := httptest.NewRequest(http.MethodPost, "/checkout", nil)
+		r.AddCookie(&http.Cookie{Name: fieldVisitorSessionGUID, Value: testTncpwSession})
+
+		var conversion *Conversion
+		handler := SessionMiddleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			conversion, _, err = client.CreateConversionForRequest(r, WithGoalName(testGoalName))
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, conversion)
+		assert.Equal(t, testTncpwSession, sent[fieldVisitorSessionGUID])
+		assert.Equal(t, testGoalName, sent[fieldName])
+	})
+
+	t.Run("no session", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		r := httptest.NewRequest(http.MethodPost, "/checkout", nil)
+
+		var conversion *Conversion
+		conversion, _, err = client.CreateConversionForRequest(r, WithGoalName(testGoalName))
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Nil(t, conversion)
+	})
+}
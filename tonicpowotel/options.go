@@ -0,0 +1,57 @@
+package tonicpowotel
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option allow functional options to be supplied
+// that overwrite default middleware options.
+type Option func(o *options)
+
+// options holds all the configuration for the middleware
+type options struct {
+	errorHandler   func(err error)               // Handles errors creating the metric instruments
+	meterProvider  metric.MeterProvider          // Creates the meter for the metrics
+	propagator     propagation.TextMapPropagator // Injects the trace context headers
+	tracerProvider trace.TracerProvider          // Creates the tracer for the spans
+}
+
+// defaultOptions will return the options using the global OpenTelemetry providers
+func defaultOptions() *options {
+	return &options{
+		errorHandler:   otel.Handle,
+		meterProvider:  otel.GetMeterProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+		tracerProvider: otel.GetTracerProvider(),
+	}
+}
+
+// WithTracerProvider will set the TracerProvider (default is the global provider)
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *options) {
+		if provider != nil {
+			o.tracerProvider = provider
+		}
+	}
+}
+
+// WithMeterProvider will set the MeterProvider (default is the global provider)
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(o *options) {
+		if provider != nil {
+			o.meterProvider = provider
+		}
+	}
+}
+
+// WithPropagator will set the propagator for the trace context headers (default is the global propagator)
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(o *options) {
+		if propagator != nil {
+			o.propagator = propagator
+		}
+	}
+}
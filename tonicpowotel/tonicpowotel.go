@@ -0,0 +1,227 @@
+// Package tonicpowotel is the OpenTelemetry instrumentation for the go-tonicpow client
+//
+// It creates a span per API call (named after the service method) with an event for
+// every attempt (including retries), records request duration & count metrics
+// and propagates the trace context headers to the API.
+//
+// Usage:
+//
+//	client, err := tonicpow.NewClient(
+//		tonicpow.WithAPIKey(apiKey),
+//		tonicpowotel.WithInstrumentation(),
+//	)
+package tonicpowotel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tonicpow/go-tonicpow"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// instrumentationName is the name of the tracer & meter
+	instrumentationName = "github.com/tonicpow/go-tonicpow/tonicpowotel"
+
+	// Event names
+	eventAttempt = "tonicpow.attempt"
+
+	// Metric names
+	metricRequestDuration = "tonicpow.client.request.duration"
+	metricRequests        = "tonicpow.client.requests"
+
+	// Attribute keys for the TonicPow resources
+	attributeAdvertiserProfileID = attribute.Key("tonicpow.advertiser_profile_id")
+	attributeAttempt             = attribute.Key("tonicpow.attempt")
+	attributeCampaignID          = attribute.Key("tonicpow.campaign_id")
+	attributeConversionID        = attribute.Key("tonicpow.conversion_id")
+	attributeGoalID              = attribute.Key("tonicpow.goal_id")
+	attributeOperation           = attribute.Key("tonicpow.operation")
+	attributeRequestGUID         = attribute.Key("tonicpow.request_guid")
+)
+
+// instruments holds the tracer, metric instruments and propagator used by the middleware
+type instruments struct {
+	duration   metric.Float64Histogram
+	propagator propagation.TextMapPropagator
+	requests   metric.Int64Counter
+	tracer     trace.Tracer
+}
+
+// WithInstrumentation will return a tonicpow.ClientOps that instruments every API call
+//
+// If no options are given, it will use the global OpenTelemetry providers & propagator
+func WithInstrumentation(opts ...Option) tonicpow.ClientOps {
+	i := newInstruments(opts...)
+	return func(c *tonicpow.ClientOptions) {
+		tonicpow.WithCallMiddleware(i.callMiddleware)(c)
+		tonicpow.WithMiddleware(i.attemptMiddleware)(c)
+	}
+}
+
+// newInstruments will create the tracer, metric instruments and propagator
+func newInstruments(opts ...Option) *instruments {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	meter := options.meterProvider.Meter(instrumentationName, metric.WithInstrumentationVersion(tonicpow.Version()))
+	i := &instruments{
+		propagator: options.propagator,
+		tracer: options.tracerProvider.Tracer(
+			instrumentationName, trace.WithInstrumentationVersion(tonicpow.Version()),
+		),
+	}
+
+	// Instruments fall back to no-op if they cannot be created
+	var err error
+	if i.duration, err = meter.Float64Histogram(
+		metricRequestDuration,
+		metric.WithDescription("Duration of TonicPow API requests"),
+		metric.WithUnit("s"),
+	); err != nil {
+		options.errorHandler(err)
+	}
+	if i.requests, err = meter.Int64Counter(
+		metricRequests,
+		metric.WithDescription("Number of TonicPow API requests"),
+		metric.WithUnit("{request}"),
+	); err != nil {
+		options.errorHandler(err)
+	}
+
+	return i
+}
+
+// callMiddleware will wrap the whole API call (every attempt) with a span and propagate the trace context
+func (i *instruments) callMiddleware(next tonicpow.RoundTripFunc) tonicpow.RoundTripFunc {
+	return func(ctx context.Context, req *tonicpow.APIRequest) (*tonicpow.StandardResponse, error) {
+
+		// Start the span & propagate the trace context (sent on every attempt)
+		ctx, span := i.tracer.Start(
+			ctx, spanName(req),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(requestAttributes(req)...),
+		)
+		defer span.End()
+		i.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		// Fire the request (including retries)
+		response, err := next(ctx, req)
+
+		// Record the results
+		if response != nil {
+			span.SetAttributes(semconv.HTTPResponseStatusCode(response.StatusCode))
+			if response.Error != nil && len(response.Error.RequestGUID) > 0 {
+				span.SetAttributes(attributeRequestGUID.String(response.Error.RequestGUID))
+			}
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		return response, err
+	}
+}
+
+// attemptMiddleware will record every attempt as an event on the call span and record the metrics
+func (i *instruments) attemptMiddleware(next tonicpow.RoundTripFunc) tonicpow.RoundTripFunc {
+	return func(ctx context.Context, req *tonicpow.APIRequest) (*tonicpow.StandardResponse, error) {
+		attributes := requestAttributes(req)
+
+		// Fire the request
+		start := time.Now()
+		response, err := next(ctx, req)
+		elapsed := time.Since(start)
+
+		// Record the attempt on the call span
+		event := []attribute.KeyValue{attributeAttempt.Int(req.Attempt)}
+		if response != nil {
+			statusCode := semconv.HTTPResponseStatusCode(response.StatusCode)
+			attributes = append(attributes, statusCode)
+			event = append(event, statusCode)
+		}
+		if err != nil {
+			errType := semconv.ErrorTypeKey.String(errorType(response, err))
+			attributes = append(attributes, errType)
+			event = append(event, errType)
+		}
+		trace.SpanFromContext(ctx).AddEvent(eventAttempt, trace.WithAttributes(event...))
+
+		// Record the metrics
+		set := metric.WithAttributes(attributes...)
+		if i.duration != nil {
+			i.duration.Record(ctx, elapsed.Seconds(), set)
+		}
+		if i.requests != nil {
+			i.requests.Add(ctx, 1, set)
+		}
+
+		return response, err
+	}
+}
+
+// spanName will return the span name (service method, or the HTTP method & path)
+func spanName(req *tonicpow.APIRequest) string {
+	if len(req.Operation.Name) > 0 {
+		return "TonicPow." + req.Operation.Name
+	}
+	return req.Method + " " + endpointPath(req.Endpoint)
+}
+
+// requestAttributes will return the attributes for the request
+func requestAttributes(req *tonicpow.APIRequest) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLPath(endpointPath(req.Endpoint)),
+	}
+	if len(req.Operation.Name) > 0 {
+		attributes = append(attributes, attributeOperation.String(req.Operation.Name))
+	}
+	if req.Operation.AdvertiserProfileID > 0 {
+		attributes = append(attributes, attributeAdvertiserProfileID.Int64(int64(req.Operation.AdvertiserProfileID)))
+	}
+	if req.Operation.CampaignID > 0 {
+		attributes = append(attributes, attributeCampaignID.Int64(int64(req.Operation.CampaignID)))
+	}
+	if req.Operation.ConversionID > 0 {
+		attributes = append(attributes, attributeConversionID.Int64(int64(req.Operation.ConversionID)))
+	}
+	if req.Operation.GoalID > 0 {
+		attributes = append(attributes, attributeGoalID.Int64(int64(req.Operation.GoalID)))
+	}
+	return attributes
+}
+
+// endpointPath will return the endpoint without the query string
+func endpointPath(endpoint string) string {
+	if i := strings.IndexByte(endpoint, '?'); i >= 0 {
+		return endpoint[:i]
+	}
+	return endpoint
+}
+
+// errorType will return a low-cardinality error type (status code, or the type of failure)
+func errorType(response *tonicpow.StandardResponse, err error) string {
+	switch {
+	case response != nil && response.StatusCode >= http.StatusBadRequest:
+		return strconv.Itoa(response.StatusCode)
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	}
+	return "_OTHER"
+}
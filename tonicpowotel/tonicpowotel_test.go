@@ -0,0 +1,194 @@
+package tonicpowotel
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tonicpow/go-tonicpow"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	testAPIKey string = "TestAPIKey12345678987654321"
+	testGoalID uint64 = 13
+)
+
+// newTestServer will return a test server that returns a goal (or 404 for unknown goals)
+func newTestServer(t *testing.T, traceParent *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if traceParent != nil {
+			*traceParent = r.Header.Get("traceparent")
+		}
+		if r.URL.Path != "/goals/details/13" {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(&tonicpow.Error{
+				Message:     "goal not found",
+				RequestGUID: "7f3d97a8fd67ff57861904df6118dcc8",
+				StatusCode:  http.StatusNotFound,
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&tonicpow.Goal{ID: testGoalID, Name: "example_goal"})
+	}))
+}
+
+// newTestClient will return a client using the test server and middleware
+func newTestClient(t *testing.T, serverURL string, opts ...Option) tonicpow.ClientInterface {
+	client, err := tonicpow.NewClient(
+		tonicpow.WithAPIKey(testAPIKey),
+		tonicpow.WithCustomEnvironment("test", "test", serverURL),
+		WithInstrumentation(opts...),
+	)
+	require.NoError(t, err)
+	return client
+}
+
+// TestWithInstrumentation will test the method WithInstrumentation()
+func TestWithInstrumentation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no-op providers", func(t *testing.T) {
+		server := newTestServer(t, nil)
+		defer server.Close()
+
+		client := newTestClient(t, server.URL,
+			WithTracerProvider(tracenoop.NewTracerProvider()),
+			WithMeterProvider(metricnoop.NewMeterProvider()),
+		)
+
+		goal, _, err := client.GetGoal(testGoalID)
+		require.NoError(t, err)
+		assert.Equal(t, testGoalID, goal.ID)
+	})
+
+	t.Run("span, metrics & propagation", func(t *testing.T) {
+		var traceParent string
+		server := newTestServer(t, &traceParent)
+		defer server.Close()
+
+		recorder := tracetest.NewSpanRecorder()
+		reader := sdkmetric.NewManualReader()
+		client := newTestClient(t, server.URL,
+			WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+			WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+			WithPropagator(propagation.TraceContext{}),
+		)
+
+		_, _, err := client.GetGoal(testGoalID)
+		require.NoError(t, err)
+
+		// Span
+		spans := recorder.Ended()
+		require.Equal(t, 1, len(spans))
+		assert.Equal(t, "TonicPow.GetGoal", spans[0].Name())
+		assert.Contains(t, spans[0].Attributes(), attributeGoalID.Int64(int64(testGoalID)))
+		assert.Contains(t, spans[0].Attributes(), attribute.Int("http.response.status_code", http.StatusOK))
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+		// Propagation
+		assert.Contains(t, traceParent, spans[0].SpanContext().TraceID().String())
+
+		// Metrics
+		var data metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(context.Background(), &data))
+		require.Equal(t, 1, len(data.ScopeMetrics))
+		names := map[string]bool{}
+		for _, m := range data.ScopeMetrics[0].Metrics {
+			names[m.Name] = true
+		}
+		assert.True(t, names[metricRequestDuration])
+		assert.True(t, names[metricRequests])
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		server := newTestServer(t, nil)
+		defer server.Close()
+
+		recorder := tracetest.NewSpanRecorder()
+		client := newTestClient(t, server.URL,
+			WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+			WithMeterProvider(metricnoop.NewMeterProvider()),
+		)
+
+		_, _, err := client.GetGoal(99)
+		require.ErrorIs(t, err, tonicpow.ErrNotFound)
+
+		spans := recorder.Ended()
+		require.Equal(t, 1, len(spans))
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Contains(t, spans[0].Attributes(), attributeRequestGUID.String("7f3d97a8fd67ff57861904df6118dcc8"))
+	})
+
+	t.Run("one span per call with retries", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts++; attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(&tonicpow.Goal{ID: testGoalID, Name: "example_goal"})
+		}))
+		defer server.Close()
+
+		policy := tonicpow.DefaultRetryPolicy()
+		policy.BaseDelay = time.Millisecond
+		policy.Jitter = 0
+
+		recorder := tracetest.NewSpanRecorder()
+		client, err := tonicpow.NewClient(
+			tonicpow.WithAPIKey(testAPIKey),
+			tonicpow.WithCustomEnvironment("test", "test", server.URL),
+			tonicpow.WithRetryPolicy(policy),
+			WithInstrumentation(
+				WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+				WithMeterProvider(metricnoop.NewMeterProvider()),
+			),
+		)
+		require.NoError(t, err)
+
+		_, _, err = client.GetGoal(testGoalID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+
+		spans := recorder.Ended()
+		require.Equal(t, 1, len(spans))
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+		events := spans[0].Events()
+		require.Equal(t, 2, len(events))
+		assert.Equal(t, eventAttempt, events[0].Name)
+		assert.Contains(t, events[0].Attributes, attributeAttempt.Int(1))
+		assert.Contains(t, events[0].Attributes, attribute.Int("http.response.status_code", http.StatusServiceUnavailable))
+		assert.Contains(t, events[1].Attributes, attributeAttempt.Int(2))
+		assert.Contains(t, events[1].Attributes, attribute.Int("http.response.status_code", http.StatusOK))
+	})
+}
+
+// TestSpanName will test the method spanName()
+func TestSpanName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "TonicPow.GetCampaign", spanName(&tonicpow.APIRequest{
+		Method:    http.MethodGet,
+		Endpoint:  "/campaigns/details/?id=1",
+		Operation: tonicpow.Operation{Name: "GetCampaign"},
+	}))
+	assert.Equal(t, "GET /campaigns/details/", spanName(&tonicpow.APIRequest{
+		Method:   http.MethodGet,
+		Endpoint: "/campaigns/details/?id=1",
+	}))
+}
@@ -1,5 +1,12 @@
 package tonicpow
 
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
 // isInList checks if string is known or not
 func isInList(test string, list []string) bool {
 	for _, a := range list {
@@ -9,3 +16,55 @@ func isInList(test string, list []string) bool {
 	}
 	return false
 }
+
+// parseAPITime will parse a time from the API (zero time if not set or invalid)
+func parseAPITime(value string) time.Time {
+	return parseFeedTime(value, apiTimeFormat, time.RFC3339)
+}
+
+// writeFileAtomic will write the file by replacing it (the file is complete or unchanged)
+//
+// The file and its directory are synced, so the new file survives a crash
+func writeFileAtomic(path string, data []byte) (err error) {
+	var tmp *os.File
+	if tmp, err = os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp"); err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = tmp.Write(data); err != nil {
+		return
+	}
+	if err = tmp.Sync(); err != nil {
+		return
+	}
+	if err = tmp.Close(); err != nil {
+		return
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir will sync the directory to disk (so a rename survives a crash)
+//
+// Directories cannot be synced on Windows (renames are durable once they return)
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir) //nolint:gosec // directory of the written file
+	if err != nil {
+		return err
+	}
+	if err = d.Sync(); err != nil {
+		_ = d.Close()
+		return err
+	}
+	return d.Close()
+}
@@ -0,0 +1,193 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HandlerFunc handles a webhook event
+//
+// Returning an error responds with a 500 (the delivery is retried)
+type HandlerFunc func(ctx context.Context, event *Event) error
+
+// Handler is an http.Handler that verifies webhook deliveries and dispatches the events
+//
+// Responses:
+//   - 204: event handled, already received (duplicate) or no handler for the type
+//   - 400: event is not valid
+//   - 401: the verifier rejected the delivery (IE: signature or timestamp is not valid)
+//   - 405: method is not POST
+//   - 413: body is too large
+//   - 500: the handler returned an error
+type Handler struct {
+	handlers  map[EventType]HandlerFunc
+	lastPrune time.Time
+	mu        sync.RWMutex
+	options   *options
+	seen      map[string]time.Time
+	verifier  Verifier
+}
+
+// NewHandler will return a new handler that accepts deliveries passing the verifier (see: NewHMACVerifier)
+func NewHandler(verifier Verifier, opts ...Option) (*Handler, error) {
+	if verifier == nil {
+		return nil, ErrMissingVerifier
+	}
+	o := &options{
+		decoder:      decodeEvent,
+		maxBodySize:  defaultMaxBodySize,
+		now:          time.Now,
+		replayWindow: defaultReplayWindow,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &Handler{
+		handlers: make(map[EventType]HandlerFunc),
+		options:  o,
+		seen:     make(map[string]time.Time),
+		verifier: verifier,
+	}, nil
+}
+
+// On will register the handler for the event type (replacing any existing handler)
+func (h *Handler) On(eventType EventType, fn HandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[eventType] = fn
+}
+
+// OnConversion will register a handler for a conversion.* event type
+//
+// Returns ErrInvalidEventType if the event type is not a conversion event
+func (h *Handler) OnConversion(eventType EventType, fn func(ctx context.Context, event *ConversionEvent) error) error {
+	if !eventType.IsConversion() {
+		return fmt.Errorf("%w: %s is not a conversion event type", ErrInvalidEventType, eventType)
+	}
+	h.On(eventType, func(ctx context.Context, event *Event) error {
+		conversionEvent, err := event.ConversionEvent()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, conversionEvent)
+	})
+	return nil
+}
+
+// OnCampaign will register a handler for a campaign.* event type
+//
+// Returns ErrInvalidEventType if the event type is not a campaign event
+func (h *Handler) OnCampaign(eventType EventType, fn func(ctx context.Context, event *CampaignEvent) error) error {
+	if !eventType.IsCampaign() {
+		return fmt.Errorf("%w: %s is not a campaign event type", ErrInvalidEventType, eventType)
+	}
+	h.On(eventType, func(ctx context.Context, event *Event) error {
+		campaignEvent, err := event.CampaignEvent()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, campaignEvent)
+	})
+	return nil
+}
+
+// ServeHTTP will verify the delivery and dispatch the event (implements http.Handler)
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.fail(w, r, http.StatusMethodNotAllowed, errors.New("method not allowed: "+r.Method))
+		return
+	}
+
+	// Read the body (limited)
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.options.maxBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.fail(w, r, http.StatusRequestEntityTooLarge, err)
+		} else {
+			h.fail(w, r, http.StatusBadRequest, err)
+		}
+		return
+	}
+
+	// Verify the delivery
+	if err = h.verifier.Verify(r, body); err != nil {
+		h.fail(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	// Decode the event
+	var event *Event
+	if event, err = h.options.decoder(r, body); err != nil {
+		h.fail(w, r, http.StatusBadRequest, err)
+		return
+	} else if event == nil || len(event.ID) == 0 || len(event.Type) == 0 {
+		h.fail(w, r, http.StatusBadRequest, errors.Join(ErrInvalidEvent, errors.New("missing id or type")))
+		return
+	}
+
+	h.mu.RLock()
+	fn, ok := h.handlers[event.Type]
+	h.mu.RUnlock()
+	if !ok || !h.markSeen(event.ID) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// Dispatch (the event can be received again if handling fails)
+	if err = fn(r.Context(), event); err != nil {
+		h.unmarkSeen(event.ID)
+		if errors.Is(err, ErrInvalidEvent) {
+			h.fail(w, r, http.StatusBadRequest, err)
+		} else {
+			h.fail(w, r, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// markSeen will record the event ID, returns false if the event was already received
+//
+// IDs are kept for the replay window (see: WithReplayWindow)
+func (h *Handler) markSeen(id string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.options.now()
+	if now.Sub(h.lastPrune) > h.options.replayWindow/2 {
+		for seenID, seenAt := range h.seen {
+			if now.Sub(seenAt) > h.options.replayWindow {
+				delete(h.seen, seenID)
+			}
+		}
+		h.lastPrune = now
+	}
+
+	if _, ok := h.seen[id]; ok {
+		return false
+	}
+	h.seen[id] = now
+	return true
+}
+
+// unmarkSeen will forget the event ID
+func (h *Handler) unmarkSeen(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.seen, id)
+}
+
+// fail will respond with the status code and report the error
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	if h.options.errorHandler != nil {
+		h.options.errorHandler(r, err)
+	}
+	http.Error(w, http.StatusText(statusCode), statusCode)
+}
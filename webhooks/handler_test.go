@@ -0,0 +1,299 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonicpow/go-tonicpow"
+)
+
+// newTestDelivery will return a signed delivery
+func newTestDelivery(secret string, signedAt time.Time, body []byte) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(body))
+	r.Header.Set(DefaultSignatureHeader, Sign(secret, signedAt, body))
+	r.Header.Set(DefaultTimestampHeader, strconv.FormatInt(signedAt.Unix(), 10))
+	return r
+}
+
+// newTestVerifier will return a verifier for the test secret
+func newTestVerifier(t *testing.T, opts ...HMACOption) *HMACVerifier {
+	verifier, err := NewHMACVerifier(testSecret, opts...)
+	assert.NoError(t, err)
+	return verifier
+}
+
+// newTestHandler will return a handler for the test secret
+func newTestHandler(t *testing.T, opts ...Option) *Handler {
+	h, err := NewHandler(newTestVerifier(t), opts...)
+	assert.NoError(t, err)
+	return h
+}
+
+// serve will serve the request and return the status code
+func serve(h http.Handler, r *http.Request) int {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code
+}
+
+// TestHandler will test the Handler
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	conversion := &tonicpow.Conversion{ID: testConversionID, Status: tonicpow.ConversionStatusPaid}
+
+	t.Run("dispatch conversion event", func(t *testing.T) {
+		var received *ConversionEvent
+		h := newTestHandler(t)
+		assert.NoError(t, h.OnConversion(EventConversionPaid, func(_ context.Context, event *ConversionEvent) error {
+			received = event
+			return nil
+		}))
+
+		body := newTestEvent(t, testEventID, EventConversionPaid, conversion)
+		assert.Equal(t, http.StatusNoContent, serve(h, newTestDelivery(testSecret, time.Now(), body)))
+		assert.NotNil(t, received)
+		assert.Equal(t, testEventID, received.ID)
+		assert.Equal(t, testConversionID, received.Conversion.ID)
+	})
+
+	t.Run("dispatch campaign event", func(t *testing.T) {
+		var received *CampaignEvent
+		h := newTestHandler(t)
+		assert.NoError(t, h.OnCampaign(EventCampaignBalanceLow, func(_ context.Context, event *CampaignEvent) error {
+			received = event
+			return nil
+		}))
+
+		body := newTestEvent(t, testEventID, EventCampaignBalanceLow, &tonicpow.Campaign{ID: 23, Balance: 1.5})
+		assert.Equal(t, http.StatusNoContent, serve(h, newTestDelivery(testSecret, time.Now(), body)))
+		assert.NotNil(t, received)
+		assert.Equal(t, 1.5, received.Campaign.Balance)
+	})
+
+	t.Run("no handler for the type", func(t *testing.T) {
+		h := newTestHandler(t)
+		body := newTestEvent(t, testEventID, EventConversionCreated, conversion)
+		assert.Equal(t, http.StatusNoContent, serve(h, newTestDelivery(testSecret, time.Now(), body)))
+	})
+
+	t.Run("replays are blocked", func(t *testing.T) {
+		var calls int
+		h := newTestHandler(t)
+		h.On(EventConversionPaid, func(context.Context, *Event) error {
+			calls++
+			return nil
+		})
+
+		body := newTestEvent(t, testEventID, EventConversionPaid, conversion)
+		signedAt := time.Now()
+		assert.Equal(t, http.StatusNoContent, serve(h, newTestDelivery(testSecret, signedAt, body)))
+		assert.Equal(t, http.StatusNoContent, serve(h, newTestDelivery(testSecret, signedAt, body)))
+		assert.Equal(t, 1, calls)
+
+		// Too old to be accepted at all
+		old := newTestDelivery(testSecret, signedAt.Add(-time.Hour), newTestEvent(t, "evt_old", EventConversionPaid, conversion))
+		assert.Equal(t, http.StatusUnauthorized, serve(h, old))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("seen events are pruned", func(t *testing.T) {
+		now := time.Now()
+		verifier := newTestVerifier(t, WithTolerance(time.Minute))
+		verifier.options.now = func() time.Time { return now }
+		h, err := NewHandler(verifier, WithReplayWindow(2*time.Minute))
+		assert.NoError(t, err)
+		h.options.now = func() time.Time { return now }
+		h.On(EventConversionPaid, func(context.Context, *Event) error { return nil })
+
+		assert.Equal(t, http.StatusNoContent, serve(h, newTestDelivery(testSecret, now,
+			newTestEvent(t, testEventID, EventConversionPaid, conversion))))
+		assert.Len(t, h.seen, 1)
+
+		now = now.Add(3 * time.Minute)
+		assert.Equal(t, http.StatusNoContent, serve(h, newTestDelivery(testSecret, now,
+			newTestEvent(t, "evt_next", EventConversionPaid, conversion))))
+		assert.Len(t, h.seen, 1)
+	})
+
+	t.Run("handler error is retried", func(t *testing.T) {
+		var calls int
+		var reported []error
+		h := newTestHandler(t, WithErrorHandler(func(_ *http.Request, err error) {
+			reported = append(reported, err)
+		}))
+		h.On(EventConversionPaid, func(context.Context, *Event) error {
+			if calls++; calls == 1 {
+				return errors.New("database is down")
+			}
+			return nil
+		})
+
+		body := newTestEvent(t, testEventID, EventConversionPaid, conversion)
+		assert.Equal(t, http.StatusInternalServerError, serve(h, newTestDelivery(testSecret, time.Now(), body)))
+		assert.Equal(t, http.StatusNoContent, serve(h, newTestDelivery(testSecret, time.Now(), body)))
+		assert.Equal(t, 2, calls)
+		assert.Len(t, reported, 1)
+	})
+
+	t.Run("invalid event data", func(t *testing.T) {
+		h := newTestHandler(t)
+		assert.NoError(t, h.OnConversion(EventConversionPaid, func(context.Context, *ConversionEvent) error { return nil }))
+
+		body := []byte(`{"id":"evt_1","type":"conversion.paid","data":"paid"}`)
+		assert.Equal(t, http.StatusBadRequest, serve(h, newTestDelivery(testSecret, time.Now(), body)))
+
+		body = []byte(`{"type":"conversion.paid"}`)
+		assert.Equal(t, http.StatusBadRequest, serve(h, newTestDelivery(testSecret, time.Now(), body)))
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		h := newTestHandler(t)
+		body := newTestEvent(t, testEventID, EventConversionPaid, conversion)
+		assert.Equal(t, http.StatusUnauthorized, serve(h, newTestDelivery("other", time.Now(), body)))
+
+		r := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(body))
+		assert.Equal(t, http.StatusUnauthorized, serve(h, r))
+	})
+
+	t.Run("rotated secrets", func(t *testing.T) {
+		verifier := newTestVerifier(t, WithSecrets("whsec_Previous", ""))
+		h, err := NewHandler(verifier)
+		assert.NoError(t, err)
+		h.On(EventConversionPaid, func(context.Context, *Event) error { return nil })
+
+		body := newTestEvent(t, testEventID, EventConversionPaid, conversion)
+		assert.Equal(t, http.StatusNoContent, serve(h, newTestDelivery("whsec_Previous", time.Now(), body)))
+		assert.Len(t, verifier.options.secrets, 2)
+	})
+
+	t.Run("custom signature headers", func(t *testing.T) {
+		verifier := newTestVerifier(t, WithSignatureHeaders("X-Signature", "X-Timestamp"))
+		h, err := NewHandler(verifier)
+		assert.NoError(t, err)
+
+		body := newTestEvent(t, testEventID, EventConversionPaid, conversion)
+		assert.Equal(t, http.StatusUnauthorized, serve(h, newTestDelivery(testSecret, time.Now(), body)))
+
+		now := time.Now()
+		r := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(body))
+		r.Header.Set("X-Signature", Sign(testSecret, now, body))
+		r.Header.Set("X-Timestamp", strconv.FormatInt(now.Unix(), 10))
+		assert.Equal(t, http.StatusNoContent, serve(h, r))
+	})
+
+	t.Run("custom verifier and decoder", func(t *testing.T) {
+		var received *Event
+		verifier := VerifierFunc(func(r *http.Request, _ []byte) error {
+			if r.Header.Get("Authorization") != "Bearer "+testSecret {
+				return ErrInvalidSignature
+			}
+			return nil
+		})
+		decoder := func(_ *http.Request, body []byte) (*Event, error) {
+			var payload struct {
+				Event      string              `json:"event"`
+				ID         string              `json:"event_id"`
+				Conversion tonicpow.Conversion `json:"conversion"`
+			}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				return nil, errors.Join(ErrInvalidEvent, err)
+			}
+			data, err := json.Marshal(payload.Conversion)
+			if err != nil {
+				return nil, err
+			}
+			return &Event{Data: data, ID: payload.ID, Type: EventType("conversion." + payload.Event)}, nil
+		}
+		h, err := NewHandler(verifier, WithDecoder(decoder))
+		assert.NoError(t, err)
+		assert.NoError(t, h.OnConversion(EventConversionPaid, func(_ context.Context, event *ConversionEvent) error {
+			received = &event.Event
+			assert.Equal(t, testConversionID, event.Conversion.ID)
+			return nil
+		}))
+
+		body := []byte(`{"event":"paid","event_id":"evt_custom","conversion":{"id":99}}`)
+		r := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(body))
+		assert.Equal(t, http.StatusUnauthorized, serve(h, r))
+
+		r = httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(body))
+		r.Header.Set("Authorization", "Bearer "+testSecret)
+		assert.Equal(t, http.StatusNoContent, serve(h, r))
+		if assert.NotNil(t, received) {
+			assert.Equal(t, "evt_custom", received.ID)
+		}
+
+		r = httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader([]byte(`{"event":"paid"}`)))
+		r.Header.Set("Authorization", "Bearer "+testSecret)
+		assert.Equal(t, http.StatusBadRequest, serve(h, r))
+	})
+
+	t.Run("missing secret or verifier", func(t *testing.T) {
+		verifier, err := NewHMACVerifier("")
+		assert.ErrorIs(t, err, ErrMissingSecret)
+		assert.Nil(t, verifier)
+
+		var h *Handler
+		h, err = NewHandler(nil)
+		assert.ErrorIs(t, err, ErrMissingVerifier)
+		assert.Nil(t, h)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		h := newTestHandler(t)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, http.MethodPost, w.Header().Get("Allow"))
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		h := newTestHandler(t, WithMaxBodySize(16))
+		body := []byte(strings.Repeat("x", 32))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, serve(h, newTestDelivery(testSecret, time.Now(), body)))
+	})
+
+	t.Run("wrong event type for handler", func(t *testing.T) {
+		h := newTestHandler(t)
+		err := h.OnConversion(EventCampaignExpired, func(context.Context, *ConversionEvent) error { return nil })
+		assert.ErrorIs(t, err, ErrInvalidEventType)
+		err = h.OnCampaign(EventConversionPaid, func(context.Context, *CampaignEvent) error { return nil })
+		assert.ErrorIs(t, err, ErrInvalidEventType)
+		assert.Empty(t, h.handlers)
+	})
+
+	t.Run("concurrent deliveries", func(t *testing.T) {
+		var mu sync.Mutex
+		var calls int
+		h := newTestHandler(t)
+		h.On(EventConversionPaid, func(context.Context, *Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			return nil
+		})
+
+		body := newTestEvent(t, testEventID, EventConversionPaid, conversion)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				serve(h, newTestDelivery(testSecret, time.Now(), body))
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,68 @@
+package webhooks
+
+import (
+	"net/http"
+	"time"
+)
+
+// Defaults for the handler
+const (
+	defaultMaxBodySize  = 1 << 20              // Maximum size of a delivery (1 MB)
+	defaultReplayWindow = 2 * defaultTolerance // How long event IDs are remembered
+	defaultTolerance    = 5 * time.Minute      // Maximum age of a delivery (HMACVerifier)
+)
+
+// Option allow functional options to be supplied
+// that overwrite default handler options.
+type Option func(o *options)
+
+// options holds all the configuration for the handler
+type options struct {
+	decoder      Decoder                          // Decodes the deliveries
+	errorHandler func(r *http.Request, err error) // Called when a delivery is rejected or a handler fails
+	maxBodySize  int64                            // Maximum size of a delivery
+	now          func() time.Time                 // Clock (for testing)
+	replayWindow time.Duration                    // How long event IDs are remembered
+}
+
+// WithDecoder will set the decoder for the deliveries (IE: to map another payload format or event names)
+// Default is ParseEvent.
+func WithDecoder(decoder Decoder) Option {
+	return func(o *options) {
+		if decoder != nil {
+			o.decoder = decoder
+		}
+	}
+}
+
+// WithMaxBodySize will set the maximum size of a delivery in bytes
+// Default is 1 MB.
+func WithMaxBodySize(size int64) Option {
+	return func(o *options) {
+		if size > 0 {
+			o.maxBodySize = size
+		}
+	}
+}
+
+// WithReplayWindow will set how long event IDs are remembered (a delivery with a known ID is not dispatched again)
+// Use at least the tolerance of the verifier. Default is 10 minutes.
+func WithReplayWindow(window time.Duration) Option {
+	return func(o *options) {
+		if window > 0 {
+			o.replayWindow = window
+		}
+	}
+}
+
+// WithErrorHandler will set a function that is called when a delivery is rejected or a handler fails
+func WithErrorHandler(handler func(r *http.Request, err error)) Option {
+	return func(o *options) {
+		o.errorHandler = handler
+	}
+}
+
+// decodeEvent is the default decoder (see: ParseEvent)
+func decodeEvent(_ *http.Request, body []byte) (*Event, error) {
+	return ParseEvent(body)
+}
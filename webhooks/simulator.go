@@ -0,0 +1,331 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mathrand "math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+// Defaults for the simulator
+const (
+	defaultSimulatorTimeout = 10 * time.Second // Timeout for each delivery (Deliver)
+	simulatorUserAgent      = "go-tonicpow-webhooks-simulator: "
+)
+
+// EventTypes will return all the webhook event types
+func EventTypes() []EventType {
+	return []EventType{
+		EventCampaignBalanceLow,
+		EventCampaignExpired,
+		EventConversionCancelled,
+		EventConversionCreated,
+		EventConversionFailed,
+		EventConversionPaid,
+	}
+}
+
+// NewEvent will return a new event (with a random ID) for the data
+func NewEvent(eventType EventType, data interface{}) (*Event, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, 12)
+	if _, err = rand.Read(b); err != nil {
+		return nil, err
+	}
+	return &Event{
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Data:      encoded,
+		ID:        "evt_" + hex.EncodeToString(b),
+		Type:      eventType,
+	}, nil
+}
+
+// SampleEvent will return an event with sample data for the event type
+func SampleEvent(eventType EventType) (*Event, error) {
+	conversion := &tonicpow.Conversion{
+		Amount:     0.25,
+		CampaignID: 23,
+		GoalID:     13,
+		GoalName:   "example_goal",
+		ID:         99,
+		UserID:     43,
+	}
+	campaign := &tonicpow.Campaign{
+		Balance:               25,
+		BalanceAlertThreshold: 5,
+		Currency:              "usd",
+		ID:                    23,
+		Slug:                  "example-campaign",
+		TargetURL:             "https://tonicpow.com",
+		Title:                 "Example Campaign",
+	}
+
+	switch eventType {
+	case EventConversionCreated:
+		conversion.Status = tonicpow.ConversionStatusPending
+		conversion.PayoutAfter = time.Now().UTC().Add(time.Hour).Format("2006-01-02 15:04:05")
+		return NewEvent(eventType, conversion)
+	case EventConversionPaid:
+		conversion.Status = tonicpow.ConversionStatusPaid
+		conversion.TxID = "e1a7c0f2b9d84c6e5a3f1b0d9c8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e"
+		return NewEvent(eventType, conversion)
+	case EventConversionFailed:
+		conversion.Status = tonicpow.ConversionStatusFailed
+		conversion.StatusData = "campaign balance is too low"
+		return NewEvent(eventType, conversion)
+	case EventConversionCancelled:
+		conversion.Status = tonicpow.ConversionStatusCancelled
+		conversion.StatusData = "order was refunded"
+		return NewEvent(eventType, conversion)
+	case EventCampaignBalanceLow:
+		campaign.Balance = 1.5
+		return NewEvent(eventType, campaign)
+	case EventCampaignExpired:
+		campaign.ExpiresAt = time.Now().UTC().Add(-time.Minute).Format("2006-01-02 15:04:05")
+		return NewEvent(eventType, campaign)
+	}
+	return nil, fmt.Errorf("%w: unknown event type %s", ErrInvalidEvent, eventType)
+}
+
+// Delivery is the result of one delivery attempt
+type Delivery struct {
+	Attempt    int    // Attempt number for the event (starts at 1)
+	Duplicate  bool   // Delivery is a duplicate of a successful delivery
+	Err        error  // Error sending the delivery (transport error or a non-2xx status code)
+	Event      *Event // Event that was delivered
+	StatusCode int    // Status code of the response (0 if there was no response)
+}
+
+// Succeeded will return true if the delivery was acknowledged (2xx)
+func (d Delivery) Succeeded() bool {
+	return d.Err == nil
+}
+
+// SimulatorOption allow functional options to be supplied
+// that overwrite default simulator options.
+type SimulatorOption func(o *simulatorOptions)
+
+// simulatorOptions holds all the configuration for the simulator
+type simulatorOptions struct {
+	duplicates      int              // Extra deliveries of each successful event
+	httpClient      *http.Client     // Client for Deliver
+	now             func() time.Time // Clock for the signatures
+	retries         int              // Retries after a failed delivery
+	retryBackoff    time.Duration    // Delay between retries
+	shuffle         *mathrand.Rand   // Shuffles the events (nil keeps the order)
+	signatureHeader string           // Header with the signature
+	timestampHeader string           // Header with the timestamp
+}
+
+// WithRetries will retry failed deliveries (transport errors or non-2xx status codes)
+// Default is no retries.
+func WithRetries(retries int, backoff time.Duration) SimulatorOption {
+	return func(o *simulatorOptions) {
+		o.retries = retries
+		o.retryBackoff = backoff
+	}
+}
+
+// WithDuplicates will deliver each event again (after it succeeds) the number of times
+// Default is no duplicates.
+func WithDuplicates(duplicates int) SimulatorOption {
+	return func(o *simulatorOptions) {
+		o.duplicates = duplicates
+	}
+}
+
+// WithOutOfOrder will shuffle the events before delivering (the seed makes the order repeatable)
+// Default is the given order.
+func WithOutOfOrder(seed int64) SimulatorOption {
+	return func(o *simulatorOptions) {
+		o.shuffle = mathrand.New(mathrand.NewSource(seed)) //nolint:gosec // Not used for security
+	}
+}
+
+// WithSimulatorHTTPClient will set the HTTP client used by Deliver
+// Default is a client with a 10 second timeout.
+func WithSimulatorHTTPClient(client *http.Client) SimulatorOption {
+	return func(o *simulatorOptions) {
+		if client != nil {
+			o.httpClient = client
+		}
+	}
+}
+
+// WithSimulatorSignatureHeaders will set the headers with the signature and the timestamp (see: WithSignatureHeaders)
+// Default is X-TonicPow-Signature and X-TonicPow-Timestamp.
+func WithSimulatorSignatureHeaders(signatureHeader, timestampHeader string) SimulatorOption {
+	return func(o *simulatorOptions) {
+		if len(signatureHeader) > 0 {
+			o.signatureHeader = signatureHeader
+		}
+		if len(timestampHeader) > 0 {
+			o.timestampHeader = timestampHeader
+		}
+	}
+}
+
+// Simulator delivers signed webhook events (IE: to test a webhook endpoint without TonicPow)
+type Simulator struct {
+	options *simulatorOptions
+	secret  string
+}
+
+// NewSimulator will return a new simulator that signs deliveries with the secret (see: HMACVerifier)
+func NewSimulator(secret string, opts ...SimulatorOption) *Simulator {
+	options := &simulatorOptions{
+		httpClient:      &http.Client{Timeout: defaultSimulatorTimeout},
+		now:             time.Now,
+		signatureHeader: DefaultSignatureHeader,
+		timestampHeader: DefaultTimestampHeader,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return &Simulator{options: options, secret: secret}
+}
+
+// NewRequest will return a signed delivery for the event
+func (s *Simulator) NewRequest(ctx context.Context, url string, event *Event) (*http.Request, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body)); err != nil {
+		return nil, err
+	}
+	signedAt := s.options.now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", simulatorUserAgent+tonicpow.Version())
+	req.Header.Set(s.options.signatureHeader, Sign(s.secret, signedAt, body))
+	req.Header.Set(s.options.timestampHeader, strconv.FormatInt(signedAt.Unix(), 10))
+	return req, nil
+}
+
+// Deliver will POST the events to the URL (IE: the App WebhookURL)
+func (s *Simulator) Deliver(ctx context.Context, url string, events ...*Event) []Delivery {
+	return s.deliver(ctx, events, func(event *Event) (int, error) {
+		req, err := s.NewRequest(ctx, url, event)
+		if err != nil {
+			return 0, err
+		}
+		var resp *http.Response
+		if resp, err = s.options.httpClient.Do(req); err != nil {
+			return 0, err
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode, nil
+	})
+}
+
+// DeliverTo will deliver the events to the handler (in-process, no network)
+func (s *Simulator) DeliverTo(ctx context.Context, handler http.Handler, events ...*Event) []Delivery {
+	return s.deliver(ctx, events, func(event *Event) (int, error) {
+		req, err := s.NewRequest(ctx, "/", event)
+		if err != nil {
+			return 0, err
+		}
+		w := &statusRecorder{header: make(http.Header)}
+		handler.ServeHTTP(w, req)
+		return w.status(), nil
+	})
+}
+
+// statusRecorder is an http.ResponseWriter that keeps the status code (the body is discarded)
+type statusRecorder struct {
+	header     http.Header
+	statusCode int
+}
+
+// Header will return the response headers (implements http.ResponseWriter)
+func (r *statusRecorder) Header() http.Header {
+	return r.header
+}
+
+// Write will discard the body (implements http.ResponseWriter)
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return len(b), nil
+}
+
+// WriteHeader will keep the first status code (implements http.ResponseWriter)
+func (r *statusRecorder) WriteHeader(statusCode int) {
+	if r.statusCode == 0 {
+		r.statusCode = statusCode
+	}
+}
+
+// status will return the status code (200 if the handler did not set one)
+func (r *statusRecorder) status() int {
+	if r.statusCode == 0 {
+		return http.StatusOK
+	}
+	return r.statusCode
+}
+
+// deliver will send each event (retries & duplicates) and return every attempt
+func (s *Simulator) deliver(ctx context.Context, events []*Event,
+	send func(event *Event) (int, error)) (deliveries []Delivery) {
+
+	if s.options.shuffle != nil {
+		events = append([]*Event(nil), events...)
+		s.options.shuffle.Shuffle(len(events), func(i, j int) {
+			events[i], events[j] = events[j], events[i]
+		})
+	}
+
+	for _, event := range events {
+		for attempt := 1; attempt <= s.options.retries+1; attempt++ {
+			if attempt > 1 && !sleep(ctx, s.options.retryBackoff) {
+				return
+			}
+			delivery := s.attempt(event, attempt, send)
+			deliveries = append(deliveries, delivery)
+			if delivery.Succeeded() {
+				for i := 0; i < s.options.duplicates && ctx.Err() == nil; i++ {
+					duplicate := s.attempt(event, attempt, send)
+					duplicate.Duplicate = true
+					deliveries = append(deliveries, duplicate)
+				}
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// attempt will send the event once
+func (s *Simulator) attempt(event *Event, attempt int, send func(event *Event) (int, error)) Delivery {
+	statusCode, err := send(event)
+	if err == nil && (statusCode < 200 || statusCode > 299) {
+		err = fmt.Errorf("webhook delivery failed: %d %s", statusCode, http.StatusText(statusCode))
+	}
+	return Delivery{Attempt: attempt, Err: err, Event: event, StatusCode: statusCode}
+}
+
+// sleep will wait for the delay, returns false if the context is done first
+func sleep(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
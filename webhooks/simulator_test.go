@@ -0,0 +1,259 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonicpow/go-tonicpow"
+)
+
+// TestSampleEvent will test the method SampleEvent()
+func TestSampleEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("every event type", func(t *testing.T) {
+		for _, eventType := range EventTypes() {
+			event, err := SampleEvent(eventType)
+			assert.NoError(t, err, eventType)
+			assert.Equal(t, eventType, event.Type)
+			assert.Len(t, event.ID, 28)
+
+			if eventType.IsConversion() {
+				var conversionEvent *ConversionEvent
+				conversionEvent, err = event.ConversionEvent()
+				assert.NoError(t, err)
+				assert.NotEmpty(t, conversionEvent.Conversion.Status)
+			} else {
+				_, err = event.CampaignEvent()
+				assert.NoError(t, err)
+			}
+		}
+	})
+
+	t.Run("statuses match the event", func(t *testing.T) {
+		event, err := SampleEvent(EventConversionPaid)
+		assert.NoError(t, err)
+		var conversionEvent *ConversionEvent
+		conversionEvent, err = event.ConversionEvent()
+		assert.NoError(t, err)
+		assert.Equal(t, tonicpow.ConversionStatusPaid, conversionEvent.Conversion.Status)
+		assert.NotEmpty(t, conversionEvent.Conversion.TxID)
+
+		event, err = SampleEvent(EventCampaignBalanceLow)
+		assert.NoError(t, err)
+		var campaignEvent *CampaignEvent
+		campaignEvent, err = event.CampaignEvent()
+		assert.NoError(t, err)
+		assert.Less(t, campaignEvent.Campaign.Balance, campaignEvent.Campaign.BalanceAlertThreshold)
+	})
+
+	t.Run("unknown event type", func(t *testing.T) {
+		event, err := SampleEvent("app.deleted")
+		assert.ErrorIs(t, err, ErrInvalidEvent)
+		assert.Nil(t, event)
+	})
+
+	t.Run("unique ids", func(t *testing.T) {
+		first, err := SampleEvent(EventConversionCreated)
+		assert.NoError(t, err)
+		var second *Event
+		second, err = SampleEvent(EventConversionCreated)
+		assert.NoError(t, err)
+		assert.NotEqual(t, first.ID, second.ID)
+	})
+}
+
+// sampleEvents will return a sample event for every type
+func sampleEvents(t *testing.T) (events []*Event) {
+	for _, eventType := range EventTypes() {
+		event, err := SampleEvent(eventType)
+		assert.NoError(t, err)
+		events = append(events, event)
+	}
+	return
+}
+
+// recordingHandler will return a Handler that records the received event types
+func recordingHandler(t *testing.T, received *[]EventType, mu *sync.Mutex) *Handler {
+	h := newTestHandler(t)
+	for _, eventType := range EventTypes() {
+		h.On(eventType, func(_ context.Context, event *Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+			*received = append(*received, event.Type)
+			return nil
+		})
+	}
+	return h
+}
+
+// TestSimulator will test the Simulator
+func TestSimulator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("deliver to handler", func(t *testing.T) {
+		var mu sync.Mutex
+		var received []EventType
+		deliveries := NewSimulator(testSecret).DeliverTo(
+			context.Background(), recordingHandler(t, &received, &mu), sampleEvents(t)...,
+		)
+		assert.Len(t, deliveries, len(EventTypes()))
+		for _, delivery := range deliveries {
+			assert.True(t, delivery.Succeeded())
+			assert.Equal(t, http.StatusNoContent, delivery.StatusCode)
+			assert.Equal(t, 1, delivery.Attempt)
+		}
+		assert.Equal(t, EventTypes(), received)
+	})
+
+	t.Run("deliver to url", func(t *testing.T) {
+		var mu sync.Mutex
+		var received []EventType
+		handler := recordingHandler(t, &received, &mu)
+		var userAgent string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userAgent = r.UserAgent()
+			handler.ServeHTTP(w, r)
+		}))
+		defer server.Close()
+
+		deliveries := NewSimulator(testSecret, WithSimulatorHTTPClient(server.Client())).Deliver(
+			context.Background(), server.URL, sampleEvents(t)...,
+		)
+		assert.Len(t, deliveries, len(EventTypes()))
+		assert.True(t, deliveries[0].Succeeded())
+		assert.Equal(t, EventTypes(), received)
+		assert.Contains(t, userAgent, tonicpow.Version())
+	})
+
+	t.Run("wrong secret is rejected", func(t *testing.T) {
+		var mu sync.Mutex
+		var received []EventType
+		event, err := SampleEvent(EventConversionPaid)
+		assert.NoError(t, err)
+
+		deliveries := NewSimulator("other").DeliverTo(context.Background(), recordingHandler(t, &received, &mu), event)
+		assert.Len(t, deliveries, 1)
+		assert.False(t, deliveries[0].Succeeded())
+		assert.Equal(t, http.StatusUnauthorized, deliveries[0].StatusCode)
+		assert.Empty(t, received)
+	})
+
+	t.Run("retries", func(t *testing.T) {
+		var calls int
+		h := newTestHandler(t)
+		h.On(EventConversionPaid, func(context.Context, *Event) error {
+			if calls++; calls < 3 {
+				return errors.New("temporary failure")
+			}
+			return nil
+		})
+		event, err := SampleEvent(EventConversionPaid)
+		assert.NoError(t, err)
+
+		deliveries := NewSimulator(testSecret, WithRetries(5, time.Millisecond)).DeliverTo(context.Background(), h, event)
+		assert.Len(t, deliveries, 3)
+		assert.Equal(t, http.StatusInternalServerError, deliveries[0].StatusCode)
+		assert.Error(t, deliveries[0].Err)
+		assert.Equal(t, 3, deliveries[2].Attempt)
+		assert.True(t, deliveries[2].Succeeded())
+	})
+
+	t.Run("retries are exhausted", func(t *testing.T) {
+		h := newTestHandler(t)
+		h.On(EventConversionPaid, func(context.Context, *Event) error { return errors.New("down") })
+		event, err := SampleEvent(EventConversionPaid)
+		assert.NoError(t, err)
+
+		deliveries := NewSimulator(testSecret, WithRetries(2, time.Millisecond)).DeliverTo(context.Background(), h, event)
+		assert.Len(t, deliveries, 3)
+		assert.False(t, deliveries[2].Succeeded())
+	})
+
+	t.Run("deliver to any http.Handler", func(t *testing.T) {
+		event, err := SampleEvent(EventConversionPaid)
+		assert.NoError(t, err)
+
+		ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		})
+		deliveries := NewSimulator(testSecret).DeliverTo(context.Background(), ok, event)
+		assert.Equal(t, http.StatusOK, deliveries[0].StatusCode)
+
+		gone := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusGone)
+			w.WriteHeader(http.StatusOK)
+		})
+		deliveries = NewSimulator(testSecret).DeliverTo(context.Background(), gone, event)
+		assert.Equal(t, http.StatusGone, deliveries[0].StatusCode)
+		assert.False(t, deliveries[0].Succeeded())
+	})
+
+	t.Run("custom signature headers", func(t *testing.T) {
+		event, err := SampleEvent(EventConversionPaid)
+		assert.NoError(t, err)
+
+		var h *Handler
+		h, err = NewHandler(newTestVerifier(t, WithSignatureHeaders("X-Signature", "X-Timestamp")))
+		assert.NoError(t, err)
+
+		simulator := NewSimulator(testSecret, WithSimulatorSignatureHeaders("X-Signature", "X-Timestamp"))
+		deliveries := simulator.DeliverTo(context.Background(), h, event)
+		assert.Len(t, deliveries, 1)
+		assert.True(t, deliveries[0].Succeeded())
+
+		deliveries = NewSimulator(testSecret).DeliverTo(context.Background(), h, event)
+		assert.Equal(t, http.StatusUnauthorized, deliveries[0].StatusCode)
+	})
+
+	t.Run("duplicates are delivered and ignored", func(t *testing.T) {
+		var mu sync.Mutex
+		var received []EventType
+		event, err := SampleEvent(EventConversionCreated)
+		assert.NoError(t, err)
+
+		deliveries := NewSimulator(testSecret, WithDuplicates(2)).DeliverTo(
+			context.Background(), recordingHandler(t, &received, &mu), event,
+		)
+		assert.Len(t, deliveries, 3)
+		assert.False(t, deliveries[0].Duplicate)
+		assert.True(t, deliveries[1].Duplicate)
+		assert.True(t, deliveries[2].Duplicate)
+		assert.True(t, deliveries[2].Succeeded())
+		assert.Equal(t, []EventType{EventConversionCreated}, received)
+	})
+
+	t.Run("out of order", func(t *testing.T) {
+		events := sampleEvents(t)
+		first := NewSimulator(testSecret, WithOutOfOrder(7)).DeliverTo(context.Background(), newTestHandler(t), events...)
+		second := NewSimulator(testSecret, WithOutOfOrder(7)).DeliverTo(context.Background(), newTestHandler(t), events...)
+		assert.Len(t, first, len(events))
+
+		var inOrder = true
+		for i := range first {
+			assert.Equal(t, first[i].Event.ID, second[i].Event.ID)
+			inOrder = inOrder && first[i].Event.ID == events[i].ID
+		}
+		assert.False(t, inOrder)
+		assert.Equal(t, EventCampaignBalanceLow, events[0].Type)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		h := newTestHandler(t)
+		h.On(EventConversionPaid, func(context.Context, *Event) error { return errors.New("down") })
+		event, err := SampleEvent(EventConversionPaid)
+		assert.NoError(t, err)
+
+		deliveries := NewSimulator(testSecret, WithRetries(5, time.Hour)).DeliverTo(ctx, h, event)
+		assert.Len(t, deliveries, 1)
+	})
+}
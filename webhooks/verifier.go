@@ -0,0 +1,91 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// HMACOption allow functional options to be supplied
+// that overwrite default HMAC verifier options.
+type HMACOption func(o *hmacOptions)
+
+// hmacOptions holds all the configuration for the HMAC verifier
+type hmacOptions struct {
+	now             func() time.Time // Clock (for testing)
+	secrets         []string         // Accepted secrets
+	signatureHeader string           // Header with the signature
+	timestampHeader string           // Header with the timestamp
+	tolerance       time.Duration    // Maximum age of a delivery
+}
+
+// WithSecrets will add more accepted secrets (IE: the previous secret while rotating)
+func WithSecrets(secrets ...string) HMACOption {
+	return func(o *hmacOptions) {
+		for _, secret := range secrets {
+			if len(secret) > 0 {
+				o.secrets = append(o.secrets, secret)
+			}
+		}
+	}
+}
+
+// WithSignatureHeaders will set the headers with the signature and the timestamp
+// Default is X-TonicPow-Signature and X-TonicPow-Timestamp.
+func WithSignatureHeaders(signatureHeader, timestampHeader string) HMACOption {
+	return func(o *hmacOptions) {
+		if len(signatureHeader) > 0 {
+			o.signatureHeader = signatureHeader
+		}
+		if len(timestampHeader) > 0 {
+			o.timestampHeader = timestampHeader
+		}
+	}
+}
+
+// WithTolerance will set the maximum age of a delivery (older deliveries are rejected as replays)
+// Default is 5 minutes.
+func WithTolerance(tolerance time.Duration) HMACOption {
+	return func(o *hmacOptions) {
+		if tolerance > 0 {
+			o.tolerance = tolerance
+		}
+	}
+}
+
+// HMACVerifier verifies the HMAC signature & timestamp headers of a delivery (see: Sign, Verify)
+type HMACVerifier struct {
+	options *hmacOptions
+}
+
+// NewHMACVerifier will return a new verifier that accepts deliveries signed with the secret
+func NewHMACVerifier(secret string, opts ...HMACOption) (*HMACVerifier, error) {
+	if len(secret) == 0 {
+		return nil, ErrMissingSecret
+	}
+	o := &hmacOptions{
+		now:             time.Now,
+		secrets:         []string{secret},
+		signatureHeader: DefaultSignatureHeader,
+		timestampHeader: DefaultTimestampHeader,
+		tolerance:       defaultTolerance,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &HMACVerifier{options: o}, nil
+}
+
+// Verify will check the delivery against each secret (implements Verifier)
+func (v *HMACVerifier) Verify(r *http.Request, body []byte) (err error) {
+	signature := r.Header.Get(v.options.signatureHeader)
+	timestamp := r.Header.Get(v.options.timestampHeader)
+	now := v.options.now()
+	for _, secret := range v.options.secrets {
+		if err = Verify(secret, signature, timestamp, body, v.options.tolerance, now); err == nil ||
+			errors.Is(err, ErrInvalidTimestamp) {
+			return
+		}
+	}
+	return
+}
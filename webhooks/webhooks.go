@@ -0,0 +1,199 @@
+// Package webhooks receives the TonicPow App webhooks (see: tonicpow.App WebhookURL)
+//
+// The Handler checks each delivery with a Verifier and decodes it into an Event with a
+// Decoder, then dispatches the event by type. Deliveries that fail verification or that
+// were already received are rejected.
+//
+// The TonicPow API does not document the signature or payload format of the App webhooks,
+// so neither is hard-coded. The defaults are:
+//   - HMACVerifier: the hex HMAC-SHA256 of "<timestamp>.<body>" (timestamp in unix seconds)
+//     in the X-TonicPow-Signature header, with the timestamp in the X-TonicPow-Timestamp header
+//     (the header names are options, see: WithSignatureHeaders)
+//   - ParseEvent: a JSON Event (see: WithDecoder to map other payloads & event names to an Event)
+//
+// Usage:
+//
+//	verifier, err := webhooks.NewHMACVerifier(secret)
+//	...
+//	handler, err := webhooks.NewHandler(verifier)
+//	...
+//	err = handler.OnConversion(webhooks.EventConversionPaid, func(ctx context.Context, event *webhooks.ConversionEvent) error {
+//		return markOrderPaid(ctx, event.Conversion)
+//	})
+//	...
+//	http.Handle("/tonicpow/webhooks", handler)
+//
+// The Simulator sends signed sample events (see: SampleEvent) to a URL or an
+// http.Handler, to test an endpoint without a TonicPow environment.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+// Default headers of the HMACVerifier (see: WithSignatureHeaders)
+const (
+	DefaultSignatureHeader = "X-TonicPow-Signature" // Hex HMAC-SHA256 of "<timestamp>.<body>"
+	DefaultTimestampHeader = "X-TonicPow-Timestamp" // Unix seconds when the delivery was signed
+)
+
+// EventType is the type of webhook event
+type EventType string
+
+// Webhook event types
+//
+// These are the types the handlers are registered with, a Decoder can map
+// the event names of the deliveries to them
+const (
+	EventCampaignBalanceLow  EventType = "campaign.balance_low" // Campaign balance is below the alert threshold
+	EventCampaignExpired     EventType = "campaign.expired"     // Campaign has expired
+	EventConversionCancelled EventType = "conversion.cancelled" // Conversion was cancelled (before the payout)
+	EventConversionCreated   EventType = "conversion.created"   // Conversion was created
+	EventConversionFailed    EventType = "conversion.failed"    // Conversion payout failed
+	EventConversionPaid      EventType = "conversion.paid"      // Conversion was paid (see: Conversion.TxID)
+)
+
+// IsCampaign will return true if the event data is a campaign
+func (t EventType) IsCampaign() bool {
+	return strings.HasPrefix(string(t), "campaign.")
+}
+
+// IsConversion will return true if the event data is a conversion
+func (t EventType) IsConversion() bool {
+	return strings.HasPrefix(string(t), "conversion.")
+}
+
+// Errors returned when a delivery is rejected
+var (
+	ErrInvalidEvent     = errors.New("webhook event is not valid")
+	ErrInvalidSignature = errors.New("webhook signature is not valid")
+	ErrInvalidTimestamp = errors.New("webhook timestamp is missing or outside the tolerance")
+)
+
+// Errors returned when creating a handler or verifier
+var (
+	ErrInvalidEventType = errors.New("webhook event type is not valid for the handler")
+	ErrMissingSecret    = errors.New("webhook secret is missing")
+	ErrMissingVerifier  = errors.New("webhook verifier is missing")
+)
+
+// Verifier checks a delivery (IE: the signature) before the event is decoded
+//
+// Return an error to reject the delivery with a 401 (see: HMACVerifier)
+type Verifier interface {
+	Verify(r *http.Request, body []byte) error
+}
+
+// VerifierFunc is a function that implements Verifier
+type VerifierFunc func(r *http.Request, body []byte) error
+
+// Verify will call the function (implements Verifier)
+func (f VerifierFunc) Verify(r *http.Request, body []byte) error {
+	return f(r, body)
+}
+
+// Decoder decodes a verified delivery into an event
+//
+// Return an error wrapping ErrInvalidEvent to reject the delivery with a 400 (see: ParseEvent)
+type Decoder func(r *http.Request, body []byte) (*Event, error)
+
+// Event is a webhook event
+type Event struct {
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+	ID        string          `json:"id"`
+	Type      EventType       `json:"type"`
+}
+
+// ConversionEvent is a conversion.* event
+type ConversionEvent struct {
+	Event
+	Conversion *tonicpow.Conversion
+}
+
+// CampaignEvent is a campaign.* event
+type CampaignEvent struct {
+	Event
+	Campaign *tonicpow.Campaign
+}
+
+// ParseEvent will parse the event (the body of a delivery)
+//
+// This does not verify the signature (see: Handler)
+func ParseEvent(body []byte) (*Event, error) {
+	event := new(Event)
+	if err := json.Unmarshal(body, event); err != nil {
+		return nil, errors.Join(ErrInvalidEvent, err)
+	} else if len(event.ID) == 0 || len(event.Type) == 0 {
+		return nil, errors.Join(ErrInvalidEvent, errors.New("missing id or type"))
+	}
+	return event, nil
+}
+
+// ConversionEvent will decode the data of a conversion.* event
+func (e *Event) ConversionEvent() (*ConversionEvent, error) {
+	if !e.Type.IsConversion() {
+		return nil, errors.Join(ErrInvalidEvent, errors.New("not a conversion event: "+string(e.Type)))
+	}
+	conversion := new(tonicpow.Conversion)
+	if err := json.Unmarshal(e.Data, conversion); err != nil {
+		return nil, errors.Join(ErrInvalidEvent, err)
+	}
+	return &ConversionEvent{Event: *e, Conversion: conversion}, nil
+}
+
+// CampaignEvent will decode the data of a campaign.* event
+func (e *Event) CampaignEvent() (*CampaignEvent, error) {
+	if !e.Type.IsCampaign() {
+		return nil, errors.Join(ErrInvalidEvent, errors.New("not a campaign event: "+string(e.Type)))
+	}
+	campaign := new(tonicpow.Campaign)
+	if err := json.Unmarshal(e.Data, campaign); err != nil {
+		return nil, errors.Join(ErrInvalidEvent, err)
+	}
+	return &CampaignEvent{Event: *e, Campaign: campaign}, nil
+}
+
+// Sign will return the HMAC signature of the body (the X-TonicPow-Signature header)
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify will check the signature & timestamp headers of a delivery
+//
+// The timestamp must be within the tolerance of now (in either direction).
+// An empty secret is refused (anyone could sign with it).
+func Verify(secret, signature, timestamp string, body []byte, tolerance time.Duration, now time.Time) error {
+	if len(secret) == 0 {
+		return ErrMissingSecret
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	signedAt := time.Unix(unix, 0)
+	if diff := now.Sub(signedAt); diff > tolerance || diff < -tolerance {
+		return ErrInvalidTimestamp
+	}
+
+	expected, _ := hex.DecodeString(Sign(secret, signedAt, body))
+	actual, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, actual) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonicpow/go-tonicpow"
+)
+
+const (
+	testConversionID uint64 = 99
+	testEventID             = "evt_5f1c2a9b"
+	testSecret              = "whsec_TestSecret12345"
+)
+
+// newTestEvent will return an encoded event
+func newTestEvent(t *testing.T, id string, eventType EventType, data interface{}) []byte {
+	encoded, err := json.Marshal(data)
+	assert.NoError(t, err)
+	body, err := json.Marshal(&Event{
+		CreatedAt: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+		Data:      encoded,
+		ID:        id,
+		Type:      eventType,
+	})
+	assert.NoError(t, err)
+	return body
+}
+
+// TestEventType will test the methods IsCampaign() and IsConversion()
+func TestEventType(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, EventConversionPaid.IsConversion())
+	assert.False(t, EventConversionPaid.IsCampaign())
+	assert.True(t, EventCampaignBalanceLow.IsCampaign())
+	assert.False(t, EventCampaignBalanceLow.IsConversion())
+	assert.False(t, EventType("app.updated").IsCampaign())
+}
+
+// TestParseEvent will test the method ParseEvent()
+func TestParseEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("conversion event", func(t *testing.T) {
+		body := newTestEvent(t, testEventID, EventConversionPaid, &tonicpow.Conversion{
+			ID:     testConversionID,
+			Status: tonicpow.ConversionStatusPaid,
+			TxID:   "b4d8f9e1c0a7",
+		})
+		event, err := ParseEvent(body)
+		assert.NoError(t, err)
+		assert.Equal(t, testEventID, event.ID)
+		assert.Equal(t, EventConversionPaid, event.Type)
+		assert.Equal(t, 2026, event.CreatedAt.Year())
+
+		var conversionEvent *ConversionEvent
+		conversionEvent, err = event.ConversionEvent()
+		assert.NoError(t, err)
+		assert.Equal(t, testConversionID, conversionEvent.Conversion.ID)
+		assert.Equal(t, tonicpow.ConversionStatusPaid, conversionEvent.Conversion.Status)
+		assert.Equal(t, "b4d8f9e1c0a7", conversionEvent.Conversion.TxID)
+
+		_, err = event.CampaignEvent()
+		assert.ErrorIs(t, err, ErrInvalidEvent)
+	})
+
+	t.Run("campaign event", func(t *testing.T) {
+		body := newTestEvent(t, testEventID, EventCampaignBalanceLow, &tonicpow.Campaign{
+			ID: 23, Balance: 1.5, BalanceAlertThreshold: 5,
+		})
+		event, err := ParseEvent(body)
+		assert.NoError(t, err)
+
+		var campaignEvent *CampaignEvent
+		campaignEvent, err = event.CampaignEvent()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(23), campaignEvent.Campaign.ID)
+		assert.Equal(t, 1.5, campaignEvent.Campaign.Balance)
+
+		_, err = event.ConversionEvent()
+		assert.ErrorIs(t, err, ErrInvalidEvent)
+	})
+
+	t.Run("invalid events", func(t *testing.T) {
+		for _, body := range []string{"", "not json", "{}", `{"id":"evt_1"}`, `{"type":"conversion.paid"}`} {
+			event, err := ParseEvent([]byte(body))
+			assert.ErrorIs(t, err, ErrInvalidEvent, body)
+			assert.Nil(t, event)
+		}
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		event, err := ParseEvent([]byte(`{"id":"evt_1","type":"conversion.paid","data":"paid"}`))
+		assert.NoError(t, err)
+		_, err = event.ConversionEvent()
+		assert.ErrorIs(t, err, ErrInvalidEvent)
+	})
+}
+
+// TestSign will test the methods Sign() and Verify()
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1791000000, 0)
+	body := []byte(`{"id":"evt_1"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	t.Run("known signature", func(t *testing.T) {
+		// echo -n '1791000000.{"id":"evt_1"}' | openssl dgst -sha256 -hmac whsec_TestSecret12345
+		signature := Sign(testSecret, now, body)
+		assert.Equal(t, "a6f9b750af6b0ba86986c92db9b23ce5cc2f7e59285104489c825c4b46dac634", signature)
+		assert.Equal(t, signature, Sign(testSecret, now, body))
+		assert.NotEqual(t, signature, Sign("other", now, body))
+		assert.NotEqual(t, signature, Sign(testSecret, now.Add(time.Second), body))
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		signature := Sign(testSecret, now, body)
+		assert.NoError(t, Verify(testSecret, signature, timestamp, body, time.Minute, now))
+		assert.NoError(t, Verify(testSecret, signature, timestamp, body, time.Minute, now.Add(time.Minute)))
+		assert.NoError(t, Verify(testSecret, signature, timestamp, body, time.Minute, now.Add(-time.Minute)))
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		signature := Sign(testSecret, now, body)
+		assert.ErrorIs(t, Verify("other", signature, timestamp, body, time.Minute, now), ErrInvalidSignature)
+		assert.ErrorIs(t, Verify(testSecret, signature, timestamp, []byte("{}"), time.Minute, now), ErrInvalidSignature)
+		assert.ErrorIs(t, Verify(testSecret, "", timestamp, body, time.Minute, now), ErrInvalidSignature)
+		assert.ErrorIs(t, Verify(testSecret, "zz", timestamp, body, time.Minute, now), ErrInvalidSignature)
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		signature := Sign("", now, body)
+		assert.ErrorIs(t, Verify("", signature, timestamp, body, time.Minute, now), ErrMissingSecret)
+	})
+
+	t.Run("invalid timestamp", func(t *testing.T) {
+		signature := Sign(testSecret, now, body)
+		assert.ErrorIs(t, Verify(testSecret, signature, "", body, time.Minute, now), ErrInvalidTimestamp)
+		assert.ErrorIs(t, Verify(testSecret, signature, "yesterday", body, time.Minute, now), ErrInvalidTimestamp)
+		assert.ErrorIs(t, Verify(testSecret, signature, timestamp, body, time.Minute, now.Add(2*time.Minute)), ErrInvalidTimestamp)
+		assert.ErrorIs(t, Verify(testSecret, signature, timestamp, body, time.Minute, now.Add(-2*time.Minute)), ErrInvalidTimestamp)
+	})
+}